@@ -0,0 +1,217 @@
+package videosdk
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// ParseCache 按作品规范标识缓存解析结果
+//
+// 通过Middleware注册为中间件后，按RequestKey指向同一作品的请求共享缓存结果，
+// 同一作品的并发请求只会调用一次解析器。只缓存成功的结果，返回给调用方的是缓存的副本。
+type ParseCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+	inflight   map[string]*inflightParse
+}
+
+// cacheEntry 缓存项
+type cacheEntry struct {
+	key     string
+	info    *VideoInfo
+	expires time.Time
+}
+
+// inflightParse 进行中的解析，供同一作品的并发请求等待
+type inflightParse struct {
+	done     chan struct{}
+	info     *VideoInfo
+	err      error
+	canceled bool // 发起解析的请求自身被取消或超时，等待者需要用自己的ctx重试
+}
+
+// NewParseCache 创建解析结果缓存，ttl<=0时不过期，maxEntries<=0时不限制数量
+func NewParseCache(ttl time.Duration, maxEntries int) *ParseCache {
+	return &ParseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		inflight:   make(map[string]*inflightParse),
+	}
+}
+
+// Middleware 获取缓存中间件，建议作为第一个中间件注册
+//
+// 短链接先通过上下文中的短链接解析器解析为完整链接再计算缓存键，同一作品的短链接和完整链接共享缓存。
+func (c *ParseCache) Middleware() Middleware {
+	return func(next ParseFunc) ParseFunc {
+		return func(ctx context.Context, req *ParseRequest) (*VideoInfo, error) {
+			req = resolveRequestLink(ctx, req)
+			key := RequestKey(req)
+			metrics := MetricsFromContext(ctx)
+
+			if info, ok := c.Get(key); ok {
+				metrics.CacheHit(req.Platform)
+				return info, nil
+			}
+			metrics.CacheMiss(req.Platform)
+
+			for {
+				c.mu.Lock()
+				call, ok := c.inflight[key]
+				if !ok {
+					break
+				}
+				c.mu.Unlock()
+
+				select {
+				case <-call.done:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+				// 发起者的ctx被取消或超时不代表本请求失败，重新检查缓存后自行解析
+				if call.canceled {
+					if info, ok := c.Get(key); ok {
+						return info, nil
+					}
+					continue
+				}
+				if call.err != nil {
+					return nil, call.err
+				}
+				return cloneVideoInfo(call.info), nil
+			}
+			call := &inflightParse{done: make(chan struct{})}
+			c.inflight[key] = call
+			c.mu.Unlock()
+
+			info, err := next(ctx, req)
+			if err == nil && info != nil {
+				call.info = cloneVideoInfo(info)
+				c.Set(key, call.info)
+			}
+			call.err = err
+			call.canceled = err != nil && ctx.Err() != nil
+
+			c.mu.Lock()
+			delete(c.inflight, key)
+			c.mu.Unlock()
+			close(call.done)
+
+			return info, err
+		}
+	}
+}
+
+// resolveRequestLink 解析请求中的短链接，返回替换为完整链接的请求副本，解析失败时原样返回交由解析器报告错误
+func resolveRequestLink(ctx context.Context, req *ParseRequest) *ParseRequest {
+	if req.URL == "" {
+		return req
+	}
+	resolved, err := ResolveShortLink(ctx, req.URL, req.Proxy)
+	if err != nil || resolved == req.URL {
+		return req
+	}
+	withURL := *req
+	withURL.URL = resolved
+	return &withURL
+}
+
+// Get 按键获取缓存的解析结果副本
+func (c *ParseCache) Get(key string) (*VideoInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return cloneVideoInfo(entry.info), true
+}
+
+// Set 缓存解析结果，超出数量上限时淘汰最久未使用的结果
+func (c *ParseCache) Set(key string, info *VideoInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = time.Now().Add(c.ttl)
+	}
+	entry := &cacheEntry{key: key, info: cloneVideoInfo(info), expires: expires}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Invalidate 删除缓存的解析结果，key可以通过RequestKey或CanonicalURL.Key获取
+func (c *ParseCache) Invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+}
+
+// Len 获取缓存的结果数量（可能包含已过期但未清理的结果）
+func (c *ParseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// remove 删除缓存项（调用方需持有锁）
+func (c *ParseCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+// cloneVideoInfo 复制视频信息，Extra只复制一层
+func cloneVideoInfo(info *VideoInfo) *VideoInfo {
+	if info == nil {
+		return nil
+	}
+
+	cloned := *info
+	if info.Downloads != nil {
+		cloned.Downloads = append(make([]DownloadItem, 0, len(info.Downloads)), info.Downloads...)
+	}
+	if info.Tags != nil {
+		cloned.Tags = append(make([]string, 0, len(info.Tags)), info.Tags...)
+	}
+	if info.Related != nil {
+		cloned.Related = append(make([]RelatedItem, 0, len(info.Related)), info.Related...)
+	}
+	if info.Raw != nil {
+		cloned.Raw = append(json.RawMessage(nil), info.Raw...)
+	}
+	if info.Extra != nil {
+		cloned.Extra = make(map[string]interface{}, len(info.Extra))
+		for k, v := range info.Extra {
+			cloned.Extra[k] = v
+		}
+	}
+	return &cloned
+}
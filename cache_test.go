@@ -0,0 +1,82 @@
+package videosdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseCacheResolvesShortLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://www.kuaishou.com/short-video/3xk8fz5m2q9wdqa", http.StatusFound)
+	}))
+	defer server.Close()
+
+	resolver := NewShortLinkResolver()
+	if err := resolver.SetEndpoint("v.kuaishou.com", server.URL); err != nil {
+		t.Fatal(err)
+	}
+	ctx := withShortLinkResolver(context.Background(), resolver)
+
+	var calls int32
+	parse := NewParseCache(0, 0).Middleware()(func(ctx context.Context, req *ParseRequest) (*VideoInfo, error) {
+		atomic.AddInt32(&calls, 1)
+		return &VideoInfo{ID: "3xk8fz5m2q9wdqa"}, nil
+	})
+
+	for _, url := range []string{"https://v.kuaishou.com/abc123", "https://www.kuaishou.com/short-video/3xk8fz5m2q9wdqa"} {
+		if _, err := parse(ctx, &ParseRequest{Platform: PlatformKuaishou, URL: url}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("parser calls = %d, want 1", calls)
+	}
+}
+
+func TestParseCacheFollowerRetriesAfterLeaderCanceled(t *testing.T) {
+	started := make(chan struct{})
+	var calls int32
+	parse := NewParseCache(0, 0).Middleware()(func(ctx context.Context, req *ParseRequest) (*VideoInfo, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &VideoInfo{ID: req.VideoID}, nil
+	})
+	req := &ParseRequest{Platform: PlatformDouyin, VideoID: "7412345678901234567"}
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := parse(leaderCtx, req)
+		leaderErr <- err
+	}()
+	<-started
+
+	followerDone := make(chan error, 1)
+	go func() {
+		info, err := parse(context.Background(), req)
+		if err == nil && info.ID != req.VideoID {
+			t.Errorf("follower got %+v", info)
+		}
+		followerDone <- err
+	}()
+	// 等待跟随者进入等待状态后再取消发起者
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-leaderErr; ErrorCodeOf(err) != ErrCodeCanceled {
+		t.Errorf("leader error = %v, want canceled", err)
+	}
+	if err := <-followerDone; err != nil {
+		t.Errorf("follower error = %v, want nil", err)
+	}
+	if calls != 2 {
+		t.Errorf("parser calls = %d, want 2", calls)
+	}
+}
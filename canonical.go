@@ -0,0 +1,186 @@
+package videosdk
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// CanonicalURL 作品的规范标识
+//
+// 同一作品的短链接、分享链接、带追踪参数的链接规范化后得到相同的Key，可用作缓存和去重的键。
+type CanonicalURL struct {
+	Platform Platform `json:"platform"`        // 平台
+	ID       string   `json:"id,omitempty"`    // 作品ID，短链接未解析时为空
+	URL      string   `json:"url"`             // 规范链接，不含追踪参数；短链接未解析时为去掉参数的短链接
+	Token    string   `json:"token,omitempty"` // 访问作品所需的令牌（如小红书的xsec_token），不参与Key
+	Short    bool     `json:"short,omitempty"` // 是否为未解析的短链接
+}
+
+// Key 获取缓存和去重使用的键，如"douyin:7412345678901234567"
+func (c *CanonicalURL) Key() string {
+	if c.ID != "" {
+		return string(c.Platform) + ":" + c.ID
+	}
+	return string(c.Platform) + ":" + c.URL
+}
+
+// canonicalRule 从链接路径或查询参数中提取作品ID的规则
+type canonicalRule struct {
+	platform Platform
+	hosts    []string       // 域名后缀
+	path     *regexp.Regexp // 匹配路径，第一个分组为作品ID
+	query    string         // 从查询参数中读取作品ID，与path二选一
+	format   string         // 规范链接格式，%s为作品ID
+}
+
+// canonicalRules 各平台作品链接的规则，按顺序匹配
+var canonicalRules = []canonicalRule{
+	{PlatformDouyin, []string{"douyin.com", "iesdouyin.com"}, regexp.MustCompile(`^/(?:share/)?note/(\d+)`), "", "https://www.douyin.com/note/%s"},
+	{PlatformDouyin, []string{"douyin.com", "iesdouyin.com"}, regexp.MustCompile(`^/(?:share/)?video/(\d+)`), "", "https://www.douyin.com/video/%s"},
+	{PlatformDouyin, []string{"douyin.com"}, nil, "modal_id", "https://www.douyin.com/video/%s"},
+	{PlatformKuaishou, []string{"kuaishou.com", "chenzhongtech.com", "gifshow.com"}, regexp.MustCompile(`^/(?:short-video|fw/photo|photo)/([0-9A-Za-z_-]+)`), "", "https://www.kuaishou.com/short-video/%s"},
+	{PlatformXiaohongshu, []string{"xiaohongshu.com"}, regexp.MustCompile(`^/(?:explore|discovery/item|user/profile/[0-9a-f]+)/([0-9a-f]{24})(?:/|$)`), "", "https://www.xiaohongshu.com/explore/%s"},
+	{PlatformBilibili, []string{"bilibili.com"}, regexp.MustCompile(`^/video/(BV[0-9A-Za-z]{10}|av\d+)`), "", "https://www.bilibili.com/video/%s"},
+	{PlatformYoutube, []string{"youtube.com"}, regexp.MustCompile(`^/(?:shorts|embed|live)/([0-9A-Za-z_-]{11})`), "", "https://www.youtube.com/watch?v=%s"},
+	{PlatformYoutube, []string{"youtube.com"}, nil, "v", "https://www.youtube.com/watch?v=%s"},
+	{PlatformYoutube, []string{"youtu.be"}, regexp.MustCompile(`^/([0-9A-Za-z_-]{11})`), "", "https://www.youtube.com/watch?v=%s"},
+}
+
+// shortLinkRules 需要跟随跳转才能得到作品ID的短链接
+var shortLinkRules = []struct {
+	host   string
+	prefix string // 路径前缀，为空时匹配任意路径
+}{
+	{"v.douyin.com", ""},
+	{"v.kuaishou.com", ""},
+	{"kuaishou.com", "/f/"},
+	{"xhslink.com", ""},
+	{"b23.tv", ""},
+}
+
+// tokenParams 作品链接中需要保留的访问令牌参数
+var tokenParams = map[Platform]string{
+	PlatformXiaohongshu: "xsec_token",
+}
+
+// Canonicalize 规范化作品链接，得到平台、作品ID和规范链接
+//
+// 支持直接传入分享文案。短链接需要跟随跳转才能得到作品ID，此时返回Short为true、
+// 以去掉参数的短链接为Key的结果。无法识别平台时返回ErrCodeUnsupportedPlatform错误，
+// 链接无效或找不到作品ID时返回ErrCodeInvalidRequest错误。
+func Canonicalize(rawURL string) (*CanonicalURL, error) {
+	text := strings.TrimSpace(rawURL)
+	if !strings.HasPrefix(text, "http://") && !strings.HasPrefix(text, "https://") {
+		if extracted := ExtractURL(text); extracted != "" {
+			text = extracted
+		}
+	}
+
+	u, err := url.Parse(text)
+	if err != nil || u.Host == "" {
+		return nil, NewError(ErrCodeInvalidRequest, fmt.Errorf("invalid url: %q", rawURL))
+	}
+	host := strings.ToLower(u.Hostname())
+	platform := DetectPlatform(text)
+	if platform == "" {
+		return nil, NewError(ErrCodeUnsupportedPlatform, fmt.Errorf("unsupported url: %s", RedactURL(text)))
+	}
+
+	for _, rule := range canonicalRules {
+		if !matchHost(host, rule.hosts...) {
+			continue
+		}
+
+		var id string
+		if rule.path != nil {
+			if m := rule.path.FindStringSubmatch(u.EscapedPath()); m != nil {
+				id = m[1]
+			}
+		} else {
+			id = u.Query().Get(rule.query)
+		}
+		if id == "" {
+			continue
+		}
+
+		canonical := &CanonicalURL{
+			Platform: rule.platform,
+			ID:       id,
+			URL:      fmt.Sprintf(rule.format, id),
+		}
+		if param, ok := tokenParams[rule.platform]; ok {
+			canonical.Token = u.Query().Get(param)
+		}
+		return canonical, nil
+	}
+
+	for _, rule := range shortLinkRules {
+		if matchHost(host, rule.host) && strings.HasPrefix(u.Path, rule.prefix) && strings.Trim(u.Path, "/") != "" {
+			return &CanonicalURL{
+				Platform: platform,
+				URL:      "https://" + host + "/" + strings.Trim(u.EscapedPath(), "/") + "/",
+				Short:    true,
+			}, nil
+		}
+	}
+
+	return nil, NewError(ErrCodeInvalidRequest, fmt.Errorf("no %s work id found in url: %s", platform, RedactURL(text)))
+}
+
+// matchHost 判断主机名是否为任一域名或其子域名
+func matchHost(host string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if host == suffix || strings.HasSuffix(host, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestKey 获取解析请求的去重键
+//
+// 提供URL时使用规范化后的Key，URL无法规范化或只提供VideoID时使用"平台:VideoID"；
+// 请求原始数据的请求结果不同，键带有":source"后缀。短链接不会在这里解析，以短链接本身为键，
+// ParseCache会先解析短链接再计算键。
+func RequestKey(req *ParseRequest) string {
+	key := string(req.Platform) + ":" + req.VideoID
+	if req.URL != "" {
+		if canonical, err := Canonicalize(req.URL); err == nil && canonical.Platform == req.Platform {
+			key = canonical.Key()
+		} else {
+			key = string(req.Platform) + ":" + strings.TrimSpace(req.URL)
+		}
+	}
+	if req.Source {
+		key += ":source"
+	}
+	return key
+}
+
+// DedupeRequests 合并指向同一作品的请求
+//
+// 返回去重后的请求（保持首次出现的顺序），以及每个原请求对应的去重后请求的下标；
+// nil请求不参与合并。
+func DedupeRequests(reqs []*ParseRequest) (unique []*ParseRequest, index []int) {
+	index = make([]int, len(reqs))
+	seen := make(map[string]int, len(reqs))
+	for i, req := range reqs {
+		if req == nil {
+			index[i] = len(unique)
+			unique = append(unique, nil)
+			continue
+		}
+
+		key := RequestKey(req)
+		if j, ok := seen[key]; ok {
+			index[i] = j
+			continue
+		}
+		seen[key] = len(unique)
+		index[i] = len(unique)
+		unique = append(unique, req)
+	}
+	return unique, index
+}
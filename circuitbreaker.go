@@ -0,0 +1,134 @@
+package videosdk
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState 熔断器状态
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // 正常放行
+	CircuitOpen     CircuitState = "open"      // 熔断，拒绝请求
+	CircuitHalfOpen CircuitState = "half_open" // 试探，放行一个请求
+)
+
+// CircuitBreaker 熔断器
+//
+// 连续失败达到阈值后熔断，经过openTimeout进入半开状态放行一个试探请求，
+// 试探成功则恢复，失败则重新熔断。
+type CircuitBreaker struct {
+	mu                  sync.Mutex
+	failureThreshold    int
+	openTimeout         time.Duration
+	state               CircuitState
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool
+	lastError           string
+}
+
+// NewCircuitBreaker 创建熔断器，failureThreshold<=0时按5处理
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	if failureThreshold <= 0 {
+		failureThreshold = 5
+	}
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		state:            CircuitClosed,
+	}
+}
+
+// Allow 是否放行请求，半开状态下只放行一个试探请求
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return true
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Success 记录请求成功，关闭熔断器
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.consecutiveFailures = 0
+	b.probing = false
+	b.lastError = ""
+}
+
+// Failure 记录请求失败，连续失败达到阈值或试探失败时熔断
+func (b *CircuitBreaker) Failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures++
+	if err != nil {
+		b.lastError = err.Error()
+	}
+	if b.state == CircuitHalfOpen || b.consecutiveFailures >= b.failureThreshold {
+		b.open()
+	}
+}
+
+// Release 放弃试探请求（请求被取消等情况），不改变熔断状态
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// Trip 立即熔断（例如健康检查失败）
+func (b *CircuitBreaker) Trip(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err != nil {
+		b.lastError = err.Error()
+	}
+	b.open()
+}
+
+// State 获取当前状态
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.openTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// failures 获取连续失败次数和最近一次失败原因
+func (b *CircuitBreaker) failures() (int, string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.consecutiveFailures, b.lastError
+}
+
+// open 进入熔断状态（调用方需持有锁）
+func (b *CircuitBreaker) open() {
+	b.state = CircuitOpen
+	b.openedAt = time.Now()
+	b.probing = false
+}
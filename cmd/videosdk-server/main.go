@@ -0,0 +1,199 @@
+// videosdk-server 独立运行的SDK HTTP服务
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/jobs"
+	"github.com/caojianfei/parser/parsers"
+	"github.com/caojianfei/parser/server"
+	"github.com/caojianfei/parser/storage"
+	"github.com/caojianfei/parser/tracker"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "监听地址")
+	douyinURL := flag.String("douyin", "http://localhost:5555", "抖音API服务地址，为空时不注册")
+	kuaishouURL := flag.String("kuaishou", "http://localhost:5557", "快手API服务地址，为空时不注册")
+	xiaohongshuURL := flag.String("xiaohongshu", "http://localhost:5556", "小红书API服务地址，为空时不注册")
+	apiKeys := flag.String("api-keys", os.Getenv("VIDEOSDK_API_KEYS"), "允许访问的API Key，多个用逗号分隔")
+	origins := flag.String("cors", "", "CORS允许的来源，多个用逗号分隔，*表示全部")
+	timeout := flag.Duration("timeout", 30*time.Second, "单次解析超时时间")
+	maxBody := flag.Int64("max-body", 1<<20, "请求体大小上限（字节）")
+	logLevel := flag.String("log-level", "info", "日志级别：debug、info、warn、error")
+	parserConfigs := flag.String("parser-config", "", "配置驱动解析器的映射配置文件（JSON或YAML），多个用逗号分隔，同平台时替换内置解析器")
+	plugins := flag.String("plugin", "", "外部解析器插件，多个用逗号分隔；http://开头的为本地HTTP插件，否则为插件进程命令，如\"python3 plugin.py\"")
+	schemaMode := flag.String("schema-mode", string(videosdk.SchemaModeLenient), "后端响应结构校验模式：lenient（记录警告）或strict（返回错误）")
+	shortLinks := flag.Bool("short-links", true, "直接跟随短链接跳转获取作品链接；关闭时抖音短链接由后端的分享链接接口解析")
+	jobStore := flag.String("job-store", "memory", "异步任务的存储：memory、file:<路径>或sqlite:<路径>，为空时不提供/jobs接口")
+	jobWorkers := flag.Int("job-workers", 2, "同时执行的异步任务数")
+	downloadDir := flag.String("download-dir", "downloads", "下载任务的保存目录")
+	webhookSecret := flag.String("webhook-secret", os.Getenv("VIDEOSDK_WEBHOOK_SECRET"), "任务Webhook的HMAC签名密钥")
+	archive := flag.String("archive", "", "本地归档数据库（SQLite）路径，设置后归档解析结果并提供/tracker统计追踪接口")
+	trackInterval := flag.Duration("track-interval", time.Hour, "统计追踪的默认采集间隔")
+	trackRate := flag.Float64("track-rate", 0.2, "统计追踪时每个平台每秒允许的请求数")
+	flag.Parse()
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		log.Fatalf("无效的日志级别 %q: %v", *logLevel, err)
+	}
+	switch videosdk.SchemaMode(*schemaMode) {
+	case videosdk.SchemaModeLenient, videosdk.SchemaModeStrict:
+	default:
+		log.Fatalf("无效的结构校验模式 %q", *schemaMode)
+	}
+
+	sdk := videosdk.NewSDK()
+	sdk.SetTimeout(*timeout)
+	sdk.SetSchemaMode(videosdk.SchemaMode(*schemaMode))
+	if !*shortLinks {
+		sdk.SetShortLinkResolver(nil)
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	sdk.SetLogger(logger)
+	metrics := videosdk.NewMetrics()
+	sdk.SetMetrics(metrics)
+
+	if *douyinURL != "" {
+		if err := sdk.RegisterParser(parsers.NewDouyinParser(*douyinURL)); err != nil {
+			log.Fatalf("注册抖音解析器失败: %v", err)
+		}
+	}
+	if *kuaishouURL != "" {
+		if err := sdk.RegisterParser(parsers.NewKuaishouParser(*kuaishouURL)); err != nil {
+			log.Fatalf("注册快手解析器失败: %v", err)
+		}
+	}
+	if *xiaohongshuURL != "" {
+		if err := sdk.RegisterParser(parsers.NewXiaohongshuParser(*xiaohongshuURL)); err != nil {
+			log.Fatalf("注册小红书解析器失败: %v", err)
+		}
+	}
+
+	for _, path := range splitList(*parserConfigs) {
+		parserConfig, err := parsers.LoadParserConfig(path)
+		if err != nil {
+			log.Fatalf("加载解析器配置失败: %v", err)
+		}
+		parser, err := parsers.NewConfigurableParser(parserConfig)
+		if err != nil {
+			log.Fatalf("创建%s解析器失败: %v", path, err)
+		}
+		if err := sdk.RegisterParser(parser); err != nil {
+			log.Fatalf("注册%s解析器失败: %v", parserConfig.Platform, err)
+		}
+	}
+
+	for _, plugin := range splitList(*plugins) {
+		cfg := parsers.PluginConfig{Command: strings.Fields(plugin)}
+		if strings.HasPrefix(plugin, "http://") || strings.HasPrefix(plugin, "https://") {
+			cfg = parsers.PluginConfig{URL: plugin}
+		}
+		parser, err := parsers.NewPluginParser(cfg)
+		if err != nil {
+			log.Fatalf("加载插件%q失败: %v", plugin, err)
+		}
+		defer parser.Close()
+		if err := sdk.RegisterParser(parser); err != nil {
+			log.Fatalf("注册%s解析器失败: %v", parser.GetPlatform(), err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var manager *jobs.Manager
+	if *jobStore != "" {
+		store, err := openJobStore(*jobStore)
+		if err != nil {
+			log.Fatalf("打开任务存储失败: %v", err)
+		}
+		defer store.Close()
+
+		manager = jobs.NewManager(sdk, store, jobs.Config{
+			Workers:       *jobWorkers,
+			DownloadDir:   *downloadDir,
+			WebhookSecret: *webhookSecret,
+			Logger:        logger,
+		})
+		if err := manager.Start(ctx); err != nil {
+			log.Fatalf("启动任务管理器失败: %v", err)
+		}
+		defer manager.Close()
+	}
+
+	var statsTracker *tracker.Tracker
+	if *archive != "" {
+		store, err := storage.Open(*archive)
+		if err != nil {
+			log.Fatalf("打开归档数据库失败: %v", err)
+		}
+		defer store.Close()
+		sdk.Use(store.Middleware())
+
+		statsTracker = tracker.New(sdk, store, tracker.Config{
+			Interval:  *trackInterval,
+			RateLimit: videosdk.LimitRule{Rate: *trackRate, Burst: 1, MaxInFlight: 1},
+			Logger:    logger,
+		})
+		if err := statsTracker.Start(ctx); err != nil {
+			log.Fatalf("启动统计追踪失败: %v", err)
+		}
+		defer statsTracker.Close()
+	}
+
+	srv := server.New(sdk, server.Config{
+		APIKeys:        splitList(*apiKeys),
+		AllowedOrigins: splitList(*origins),
+		MaxBodyBytes:   *maxBody,
+		Metrics:        metrics,
+		Jobs:           manager,
+		Tracker:        statsTracker,
+	})
+
+	log.Printf("服务已启动: %s", *addr)
+	if err := srv.ListenAndServe(ctx, *addr); err != nil {
+		log.Fatalf("服务异常退出: %v", err)
+	}
+	log.Printf("服务已关闭")
+}
+
+// splitList 拆分逗号分隔的列表，忽略空项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// openJobStore 按"memory"、"file:<路径>"或"sqlite:<路径>"打开任务存储
+func openJobStore(spec string) (jobs.Store, error) {
+	kind, path, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "memory":
+		return jobs.NewMemoryStore(), nil
+	case "file":
+		if path != "" {
+			return jobs.OpenFileStore(path)
+		}
+	case "sqlite":
+		if path != "" {
+			return jobs.OpenSQLiteStore(path)
+		}
+	}
+	return nil, fmt.Errorf("无效的任务存储 %q，应为memory、file:<路径>或sqlite:<路径>", spec)
+}
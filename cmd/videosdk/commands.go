@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/server"
+)
+
+// parseFlags 解析子命令参数
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{msg: err.Error()}
+	}
+	return nil
+}
+
+// signalContext 收到中断信号时取消的上下文
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// runParse 解析单个作品
+func runParse(args []string) error {
+	fs := flag.NewFlagSet("parse", flag.ContinueOnError)
+	common := registerCommonFlags(fs)
+	jsonOutput := fs.Bool("json", false, "以JSON格式输出")
+	source := fs.Bool("source", false, "在JSON输出中包含后端返回的原始数据（raw字段）")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return &usageError{msg: "用法: videosdk parse [flags] <链接或分享文案>"}
+	}
+
+	cfg, err := common.load()
+	if err != nil {
+		return err
+	}
+	sdk, err := cfg.newSDK()
+	if err != nil {
+		return err
+	}
+	req, err := cfg.newRequest(strings.Join(fs.Args(), " "), common.cookie)
+	if err != nil {
+		return err
+	}
+	req.Source = *source
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	resp, err := sdk.ParseVideo(ctx, req)
+	if *jsonOutput {
+		if encErr := printJSON(resp); encErr != nil {
+			return encErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	printVideoInfo(resp.Data)
+	return nil
+}
+
+// runDownload 解析并下载作品媒体文件
+func runDownload(args []string) error {
+	fs := flag.NewFlagSet("download", flag.ContinueOnError)
+	common := registerCommonFlags(fs)
+	outputDir := fs.String("o", ".", "保存目录")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return &usageError{msg: "用法: videosdk download [flags] -o <目录> <链接或分享文案>"}
+	}
+
+	cfg, err := common.load()
+	if err != nil {
+		return err
+	}
+	sdk, err := cfg.newSDK()
+	if err != nil {
+		return err
+	}
+	req, err := cfg.newRequest(strings.Join(fs.Args(), " "), common.cookie)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	resp, err := sdk.ParseVideo(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	downloader := videosdk.NewDownloader()
+	if pool, err := cfg.newProxyPool(); err != nil {
+		return err
+	} else if pool != nil && cfg.Proxy == "" {
+		downloader.SetProxyPool(pool)
+	}
+
+	files, err := downloader.Download(ctx, resp.Data, *outputDir)
+	for _, file := range files {
+		fmt.Println(file)
+	}
+	return err
+}
+
+// runBatch 批量解析文件中的链接
+func runBatch(args []string) error {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	common := registerCommonFlags(fs)
+	inputFile := fs.String("f", "", "链接文件，每行一个链接或分享文案，\"-\"表示标准输入")
+	concurrency := fs.Int("c", 4, "并发数")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *inputFile == "" {
+		return &usageError{msg: "用法: videosdk batch [flags] -f <链接文件>"}
+	}
+
+	lines, err := readLines(*inputFile)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := common.load()
+	if err != nil {
+		return err
+	}
+	sdk, err := cfg.newSDK()
+	if err != nil {
+		return err
+	}
+	sdk.SetBatchConcurrency(*concurrency)
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	// 无法构建请求的行直接记为失败，其余交给SDK并发解析
+	responses := make([]*videosdk.ParseResponse, len(lines))
+	var reqs []*videosdk.ParseRequest
+	var indexes []int
+	for i, line := range lines {
+		req, err := cfg.newRequest(line, common.cookie)
+		if err != nil {
+			responses[i] = &videosdk.ParseResponse{
+				Code:  videosdk.ErrorCodeOf(err),
+				Error: err.Error(),
+			}
+			continue
+		}
+		reqs = append(reqs, req)
+		indexes = append(indexes, i)
+	}
+	for i, resp := range sdk.ParseBatch(ctx, reqs) {
+		responses[indexes[i]] = resp
+	}
+
+	// 每行输出一个JSON结果，便于后续处理
+	failed := 0
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	for i, resp := range responses {
+		if !resp.Success {
+			failed++
+		}
+		if err := encoder.Encode(struct {
+			Input string `json:"input"`
+			*videosdk.ParseResponse
+		}{lines[i], resp}); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return &partialError{failed: failed, total: len(responses)}
+	}
+	return nil
+}
+
+// runPlatforms 列出支持的平台
+func runPlatforms(args []string) error {
+	fs := flag.NewFlagSet("platforms", flag.ContinueOnError)
+	common := registerCommonFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := common.load()
+	if err != nil {
+		return err
+	}
+	sdk, err := cfg.newSDK()
+	if err != nil {
+		return err
+	}
+
+	platforms := sdk.GetSupportedPlatforms()
+	sort.Slice(platforms, func(i, j int) bool { return platforms[i] < platforms[j] })
+	for _, platform := range platforms {
+		fmt.Printf("%s\t%s\n", platform, cfg.Backends[platform])
+	}
+	return nil
+}
+
+// runServe 启动HTTP服务
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	common := registerCommonFlags(fs)
+	addr := fs.String("addr", ":8080", "监听地址")
+	apiKeys := fs.String("api-keys", os.Getenv("VIDEOSDK_API_KEYS"), "允许访问的API Key，多个用逗号分隔")
+	origins := fs.String("cors", "", "CORS允许的来源，多个用逗号分隔，*表示全部")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := common.load()
+	if err != nil {
+		return err
+	}
+	sdk, err := cfg.newSDK()
+	if err != nil {
+		return err
+	}
+
+	metrics := videosdk.NewMetrics()
+	sdk.SetMetrics(metrics)
+
+	srv := server.New(sdk, server.Config{
+		APIKeys:        splitList(*apiKeys),
+		AllowedOrigins: splitList(*origins),
+		Metrics:        metrics,
+	})
+
+	ctx, cancel := signalContext()
+	defer cancel()
+
+	fmt.Fprintf(os.Stderr, "服务已启动: %s\n", *addr)
+	return srv.ListenAndServe(ctx, *addr)
+}
+
+// readLines 读取非空且非注释的行
+func readLines(path string) ([]string, error) {
+	file := os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("打开链接文件失败: %w", err)
+		}
+		defer f.Close()
+		file = f
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取链接文件失败: %w", err)
+	}
+	return lines, nil
+}
+
+// splitList 拆分逗号分隔的列表，忽略空项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// printJSON 以缩进JSON格式输出
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printVideoInfo 以易读格式输出作品信息
+func printVideoInfo(info *videosdk.VideoInfo) {
+	fmt.Printf("平台:     %s\n", info.Platform)
+	fmt.Printf("作品ID:   %s\n", info.ID)
+	fmt.Printf("标题:     %s\n", info.Title)
+	fmt.Printf("类型:     %s\n", info.Type)
+	fmt.Printf("作者:     %s (%s)\n", info.Author.Nickname, info.Author.UID)
+	if !info.CreateTime.IsZero() {
+		fmt.Printf("发布时间: %s\n", info.CreateTime.Format("2006-01-02 15:04:05"))
+	}
+	if info.Duration > 0 {
+		fmt.Printf("时长:     %s\n", info.FormattedDuration())
+	}
+	fmt.Printf("播放/点赞/评论/分享/收藏: %s/%s/%s/%s/%s\n",
+		formatCount(info.Stats.PlayCount), formatCount(info.Stats.LikeCount),
+		formatCount(info.Stats.CommentCount), formatCount(info.Stats.ShareCount),
+		formatCount(info.Stats.CollectCount))
+	if len(info.Tags) > 0 {
+		fmt.Printf("标签:     %s\n", strings.Join(info.Tags, ", "))
+	}
+	for i, item := range info.Downloads {
+		fmt.Printf("下载[%d]:  %s (%s)\n", i+1, item.URL, item.Type)
+	}
+}
+
+// formatCount 格式化数量，未知时输出"-"
+func formatCount(c videosdk.Count) string {
+	if !c.Known() {
+		return "-"
+	}
+	return fmt.Sprintf("%d", c)
+}
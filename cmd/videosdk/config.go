@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/parsers"
+)
+
+// Config 命令行配置
+//
+// 优先级：命令行参数 > 环境变量 > 配置文件 > 默认值。
+type Config struct {
+	Backends  map[videosdk.Platform]string `json:"backends"`   // 各平台API服务地址
+	Cookies   map[videosdk.Platform]string `json:"cookies"`    // 各平台Cookie
+	Proxy     string                       `json:"proxy"`      // 代理地址
+	Timeout   string                       `json:"timeout"`    // 解析超时时间，如"30s"
+	CookieJar string                       `json:"cookie_jar"` // Cookie存储文件路径，未提供Cookie时自动使用
+	Proxies   []string                     `json:"proxies"`    // 代理池，未指定proxy时轮换使用
+	LogLevel  string                       `json:"log_level"`  // 日志级别：debug、info、warn、error，为空时不输出日志
+
+	ParserConfigs []string `json:"parser_configs"` // 配置驱动解析器的映射配置文件（JSON或YAML），同平台时替换内置解析器
+}
+
+// defaultBackends 默认的API服务地址
+var defaultBackends = map[videosdk.Platform]string{
+	videosdk.PlatformDouyin:      "http://localhost:5555",
+	videosdk.PlatformXiaohongshu: "http://localhost:5556",
+	videosdk.PlatformKuaishou:    "http://localhost:5557",
+}
+
+// commonFlags 各子命令共用的参数
+type commonFlags struct {
+	configPath string
+	backends   map[videosdk.Platform]*string
+	cookie     string
+	proxy      string
+	timeout    time.Duration
+	cookieJar  string
+	logLevel   string
+}
+
+// registerCommonFlags 注册共用参数
+func registerCommonFlags(fs *flag.FlagSet) *commonFlags {
+	c := &commonFlags{backends: make(map[videosdk.Platform]*string)}
+	fs.StringVar(&c.configPath, "config", "", "配置文件路径（默认$VIDEOSDK_CONFIG或~/.config/videosdk/config.json）")
+	fs.StringVar(&c.cookie, "cookie", "", "Cookie，未指定时使用环境变量或配置文件中对应平台的Cookie")
+	fs.StringVar(&c.proxy, "proxy", "", "代理地址")
+	fs.DurationVar(&c.timeout, "timeout", 0, "解析超时时间（默认30s）")
+	fs.StringVar(&c.cookieJar, "cookie-jar", "", "Cookie存储文件路径")
+	fs.StringVar(&c.logLevel, "log-level", "", "日志级别：debug、info、warn、error（默认不输出日志）")
+	for _, platform := range []videosdk.Platform{videosdk.PlatformDouyin, videosdk.PlatformKuaishou, videosdk.PlatformXiaohongshu} {
+		c.backends[platform] = fs.String(string(platform), "", fmt.Sprintf("%s API服务地址", platform))
+	}
+	return c
+}
+
+// load 合并命令行参数、环境变量和配置文件
+func (c *commonFlags) load() (*Config, error) {
+	cfg, err := loadConfigFile(c.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Backends == nil {
+		cfg.Backends = make(map[videosdk.Platform]string)
+	}
+	if cfg.Cookies == nil {
+		cfg.Cookies = make(map[videosdk.Platform]string)
+	}
+
+	for platform, flagValue := range c.backends {
+		envName := "VIDEOSDK_" + strings.ToUpper(string(platform))
+		switch {
+		case *flagValue != "":
+			cfg.Backends[platform] = *flagValue
+		case os.Getenv(envName+"_URL") != "":
+			cfg.Backends[platform] = os.Getenv(envName + "_URL")
+		case cfg.Backends[platform] == "":
+			cfg.Backends[platform] = defaultBackends[platform]
+		}
+
+		if cookie := os.Getenv(envName + "_COOKIE"); cookie != "" {
+			cfg.Cookies[platform] = cookie
+		}
+	}
+
+	switch {
+	case c.proxy != "":
+		cfg.Proxy = c.proxy
+	case os.Getenv("VIDEOSDK_PROXY") != "":
+		cfg.Proxy = os.Getenv("VIDEOSDK_PROXY")
+	}
+
+	switch {
+	case c.timeout > 0:
+		cfg.Timeout = c.timeout.String()
+	case os.Getenv("VIDEOSDK_TIMEOUT") != "":
+		cfg.Timeout = os.Getenv("VIDEOSDK_TIMEOUT")
+	}
+
+	switch {
+	case c.cookieJar != "":
+		cfg.CookieJar = c.cookieJar
+	case os.Getenv("VIDEOSDK_COOKIE_JAR") != "":
+		cfg.CookieJar = os.Getenv("VIDEOSDK_COOKIE_JAR")
+	}
+
+	switch {
+	case c.logLevel != "":
+		cfg.LogLevel = c.logLevel
+	case os.Getenv("VIDEOSDK_LOG_LEVEL") != "":
+		cfg.LogLevel = os.Getenv("VIDEOSDK_LOG_LEVEL")
+	}
+
+	return cfg, nil
+}
+
+// loadConfigFile 读取配置文件，未显式指定且默认路径不存在时返回空配置
+func loadConfigFile(path string) (*Config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv("VIDEOSDK_CONFIG")
+		explicit = path != ""
+	}
+	if !explicit {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return &Config{}, nil
+		}
+		path = filepath.Join(dir, "videosdk", "config.json")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+	return &cfg, nil
+}
+
+// newSDK 根据配置创建SDK实例并注册解析器
+func (cfg *Config) newSDK() (*videosdk.VideoSDK, error) {
+	sdk := videosdk.NewSDK()
+
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("无效的超时时间 %q: %w", cfg.Timeout, err)
+		}
+		sdk.SetTimeout(timeout)
+	}
+
+	if cfg.LogLevel != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+			return nil, fmt.Errorf("无效的日志级别 %q: %w", cfg.LogLevel, err)
+		}
+		sdk.SetLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+	}
+
+	if cfg.CookieJar != "" {
+		sdk.SetCookieJar(cfg.newCookieJar())
+	}
+
+	if pool, err := cfg.newProxyPool(); err != nil {
+		return nil, err
+	} else if pool != nil {
+		sdk.SetProxyPool(pool)
+	}
+
+	constructors := map[videosdk.Platform]func(string) videosdk.Parser{
+		videosdk.PlatformDouyin:      parsers.NewDouyinParser,
+		videosdk.PlatformKuaishou:    parsers.NewKuaishouParser,
+		videosdk.PlatformXiaohongshu: parsers.NewXiaohongshuParser,
+	}
+	for platform, newParser := range constructors {
+		baseURL := cfg.Backends[platform]
+		if baseURL == "" {
+			continue
+		}
+		if err := sdk.RegisterParser(newParser(baseURL)); err != nil {
+			return nil, fmt.Errorf("注册%s解析器失败: %w", platform, err)
+		}
+	}
+
+	for _, path := range cfg.ParserConfigs {
+		parserConfig, err := parsers.LoadParserConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		parser, err := parsers.NewConfigurableParser(parserConfig)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if err := sdk.RegisterParser(parser); err != nil {
+			return nil, fmt.Errorf("注册%s解析器失败: %w", parserConfig.Platform, err)
+		}
+	}
+
+	return sdk, nil
+}
+
+// newProxyPool 根据配置创建代理池，未配置代理时返回nil
+func (cfg *Config) newProxyPool() (*videosdk.ProxyPool, error) {
+	if len(cfg.Proxies) == 0 {
+		return nil, nil
+	}
+
+	pool := videosdk.NewProxyPool(videosdk.ProxyStrategyRoundRobin)
+	for _, proxy := range cfg.Proxies {
+		if err := pool.Add(proxy); err != nil {
+			return nil, fmt.Errorf("无效的代理 %q: %w", proxy, err)
+		}
+	}
+	return pool, nil
+}
+
+// newCookieJar 创建基于配置文件路径的Cookie管理器
+func (cfg *Config) newCookieJar() *videosdk.CookieJar {
+	return videosdk.NewCookieJar(videosdk.NewFileCookieStore(cfg.CookieJar))
+}
+
+// newRequest 根据链接或分享文案构建解析请求
+func (cfg *Config) newRequest(input, cookie string) (*videosdk.ParseRequest, error) {
+	rawURL := videosdk.ExtractURL(input)
+	if rawURL == "" {
+		return nil, videosdk.NewError(videosdk.ErrCodeInvalidRequest, fmt.Errorf("未在输入中找到链接: %s", input))
+	}
+
+	platform := videosdk.DetectPlatform(rawURL)
+	if platform == "" {
+		return nil, videosdk.NewError(videosdk.ErrCodeUnsupportedPlatform, fmt.Errorf("无法识别链接所属平台: %s", rawURL))
+	}
+
+	if cookie == "" {
+		cookie = cfg.Cookies[platform]
+	}
+
+	return &videosdk.ParseRequest{
+		Platform: platform,
+		URL:      rawURL,
+		Cookie:   cookie,
+		Proxy:    cfg.Proxy,
+	}, nil
+}
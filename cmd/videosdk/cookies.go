@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// runCookies 管理Cookie存储
+func runCookies(args []string) error {
+	if len(args) == 0 {
+		return &usageError{msg: "用法: videosdk cookies import|status [flags]"}
+	}
+
+	switch args[0] {
+	case "import":
+		return runCookiesImport(args[1:])
+	case "status":
+		return runCookiesStatus(args[1:])
+	default:
+		return &usageError{msg: fmt.Sprintf("未知的cookies子命令: %s", args[0])}
+	}
+}
+
+// runCookiesImport 导入Cookie文件
+func runCookiesImport(args []string) error {
+	fs := flag.NewFlagSet("cookies import", flag.ContinueOnError)
+	common := registerCommonFlags(fs)
+	platform := fs.String("platform", "", "平台（douyin、kuaishou、xiaohongshu）")
+	format := fs.String("format", "", "文件格式：netscape或json（默认根据文件内容判断）")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *platform == "" || fs.NArg() != 1 {
+		return &usageError{msg: "用法: videosdk cookies import -platform <平台> [-format netscape|json] <文件>"}
+	}
+
+	jar, err := loadCookieJar(common)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("读取Cookie文件失败: %w", err)
+	}
+
+	if *format == "" {
+		*format = "netscape"
+		if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+			*format = "json"
+		}
+	}
+
+	var count int
+	switch *format {
+	case "netscape":
+		count, err = jar.ImportNetscape(videosdk.Platform(*platform), strings.NewReader(string(data)))
+	case "json":
+		count, err = jar.ImportBrowserJSON(videosdk.Platform(*platform), strings.NewReader(string(data)))
+	default:
+		return &usageError{msg: fmt.Sprintf("不支持的格式: %s", *format)}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("已导入 %d 个%s Cookie\n", count, *platform)
+	return nil
+}
+
+// runCookiesStatus 输出Cookie过期状态
+func runCookiesStatus(args []string) error {
+	fs := flag.NewFlagSet("cookies status", flag.ContinueOnError)
+	common := registerCommonFlags(fs)
+	platform := fs.String("platform", "", "平台，为空时输出全部平台")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	jar, err := loadCookieJar(common)
+	if err != nil {
+		return err
+	}
+
+	platforms := []videosdk.Platform{videosdk.PlatformDouyin, videosdk.PlatformKuaishou, videosdk.PlatformXiaohongshu}
+	if *platform != "" {
+		platforms = []videosdk.Platform{videosdk.Platform(*platform)}
+	}
+
+	var statuses []*videosdk.CookieStatus
+	for _, p := range platforms {
+		status, err := jar.Status(p)
+		if err != nil {
+			return err
+		}
+		sort.Slice(status.Cookies, func(i, j int) bool { return status.Cookies[i].Name < status.Cookies[j].Name })
+		statuses = append(statuses, status)
+	}
+
+	return printJSON(statuses)
+}
+
+// loadCookieJar 根据配置打开Cookie存储
+func loadCookieJar(common *commonFlags) (*videosdk.CookieJar, error) {
+	cfg, err := common.load()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.CookieJar == "" {
+		return nil, &usageError{msg: "未配置Cookie存储文件，请使用-cookie-jar参数、VIDEOSDK_COOKIE_JAR环境变量或配置文件的cookie_jar字段"}
+	}
+	return cfg.newCookieJar(), nil
+}
@@ -0,0 +1,148 @@
+// videosdk 视频数据解析命令行工具
+//
+// 用法:
+//
+//	videosdk parse [flags] <链接或分享文案>
+//	videosdk download [flags] -o <目录> <链接或分享文案>
+//	videosdk batch [flags] -f <链接文件>
+//	videosdk platforms [flags]
+//	videosdk serve [flags]
+//	videosdk cookies import|status [flags]
+//
+// 退出码反映错误类别，见exitCodeFor。
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// 退出码
+const (
+	exitOK                  = 0 // 成功
+	exitError               = 1 // 其他错误
+	exitUsage               = 2 // 命令行用法或请求参数错误
+	exitUnsupportedPlatform = 3 // 平台不支持
+	exitParseFailed         = 4 // 解析失败
+	exitTimeout             = 5 // 超时、被取消或触发限流
+	exitDownloadFailed      = 6 // 下载失败
+	exitUnauthorized        = 7 // 未授权或Cookie失效
+	exitPartial             = 8 // 批量任务部分失败
+)
+
+// usageError 命令行用法错误
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// partialError 批量任务部分失败
+type partialError struct {
+	failed int
+	total  int
+}
+
+func (e *partialError) Error() string {
+	return fmt.Sprintf("%d/%d 个任务失败", e.failed, e.total)
+}
+
+const usage = `videosdk 视频数据解析命令行工具
+
+用法:
+  videosdk parse [flags] <链接或分享文案>         解析作品信息
+  videosdk download [flags] -o <目录> <链接>      解析并下载作品媒体文件
+  videosdk batch [flags] -f <链接文件>            批量解析（每行一个链接或分享文案）
+  videosdk platforms [flags]                      列出支持的平台
+  videosdk serve [flags]                          启动HTTP服务
+  videosdk cookies import [flags] <文件>          导入cookies.txt或浏览器导出的JSON Cookie
+  videosdk cookies status [flags]                 查看Cookie过期状态
+
+使用 "videosdk <命令> -h" 查看命令参数。
+
+环境变量:
+  VIDEOSDK_CONFIG                      配置文件路径
+  VIDEOSDK_{DOUYIN,KUAISHOU,XIAOHONGSHU}_URL     API服务地址
+  VIDEOSDK_{DOUYIN,KUAISHOU,XIAOHONGSHU}_COOKIE  Cookie
+  VIDEOSDK_PROXY, VIDEOSDK_TIMEOUT, VIDEOSDK_COOKIE_JAR, VIDEOSDK_LOG_LEVEL
+`
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run 执行子命令并返回退出码
+func run(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return exitUsage
+	}
+
+	commands := map[string]func([]string) error{
+		"parse":     runParse,
+		"download":  runDownload,
+		"batch":     runBatch,
+		"platforms": runPlatforms,
+		"serve":     runServe,
+		"cookies":   runCookies,
+	}
+
+	name := args[0]
+	if name == "-h" || name == "--help" || name == "help" {
+		fmt.Fprint(os.Stdout, usage)
+		return exitOK
+	}
+
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n\n%s", name, usage)
+		return exitUsage
+	}
+
+	if err := command(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		return exitCodeFor(err)
+	}
+	return exitOK
+}
+
+// exitCodeFor 错误对应的退出码
+func exitCodeFor(err error) int {
+	var uErr *usageError
+	if errors.As(err, &uErr) {
+		return exitUsage
+	}
+
+	var pErr *partialError
+	if errors.As(err, &pErr) {
+		return exitPartial
+	}
+
+	switch videosdk.ErrorCodeOf(err) {
+	case videosdk.ErrCodeInvalidRequest:
+		return exitUsage
+	case videosdk.ErrCodeUnsupportedPlatform:
+		return exitUnsupportedPlatform
+	case videosdk.ErrCodeTimeout, videosdk.ErrCodeCanceled, videosdk.ErrCodeRateLimited:
+		return exitTimeout
+	case videosdk.ErrCodeDownloadFailed:
+		return exitDownloadFailed
+	case videosdk.ErrCodeUnauthorized, videosdk.ErrCodeLoginRequired, videosdk.ErrCodeVerificationRequired, videosdk.ErrCodeTokenRequired:
+		return exitUnauthorized
+	}
+
+	var sdkErr *videosdk.Error
+	if errors.As(err, &sdkErr) {
+		return exitParseFailed
+	}
+	return exitError
+}
@@ -0,0 +1,597 @@
+package videosdk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cookie 单个Cookie
+type Cookie struct {
+	Name     string    `json:"name"`      // 名称
+	Value    string    `json:"value"`     // 值
+	Domain   string    `json:"domain"`    // 域名
+	Path     string    `json:"path"`      // 路径
+	Expires  time.Time `json:"expires"`   // 过期时间，零值表示会话Cookie
+	Secure   bool      `json:"secure"`    // 是否仅HTTPS
+	HTTPOnly bool      `json:"http_only"` // 是否HttpOnly
+}
+
+// Expired Cookie在指定时间是否已过期
+func (c *Cookie) Expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+// key Cookie的唯一标识（名称+域名+路径）
+func (c *Cookie) key() string {
+	return c.Name + "\x00" + strings.TrimPrefix(strings.ToLower(c.Domain), ".") + "\x00" + c.Path
+}
+
+// CookieStore Cookie存储接口
+type CookieStore interface {
+	// Load 读取平台的全部Cookie
+	Load(platform Platform) ([]*Cookie, error)
+
+	// Save 覆盖保存平台的全部Cookie
+	Save(platform Platform, cookies []*Cookie) error
+}
+
+// MemoryCookieStore 基于内存的Cookie存储
+type MemoryCookieStore struct {
+	mu      sync.RWMutex
+	cookies map[Platform][]*Cookie
+}
+
+// NewMemoryCookieStore 创建内存Cookie存储
+func NewMemoryCookieStore() *MemoryCookieStore {
+	return &MemoryCookieStore{cookies: make(map[Platform][]*Cookie)}
+}
+
+// Load 读取平台的全部Cookie
+func (s *MemoryCookieStore) Load(platform Platform) ([]*Cookie, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return cloneCookies(s.cookies[platform]), nil
+}
+
+// Save 覆盖保存平台的全部Cookie
+func (s *MemoryCookieStore) Save(platform Platform, cookies []*Cookie) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cookies[platform] = cloneCookies(cookies)
+	return nil
+}
+
+// FileCookieStore 基于JSON文件的Cookie存储，所有平台保存在同一个文件中
+//
+// 文件内容在首次读取后缓存在内存中，Save时同时更新缓存和文件；其他进程对文件的修改不会被感知。
+type FileCookieStore struct {
+	mu    sync.Mutex
+	path  string
+	cache map[Platform][]*Cookie
+}
+
+// NewFileCookieStore 创建文件Cookie存储
+func NewFileCookieStore(path string) *FileCookieStore {
+	return &FileCookieStore{path: path}
+}
+
+// Load 读取平台的全部Cookie
+func (s *FileCookieStore) Load(platform Platform) ([]*Cookie, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return cloneCookies(all[platform]), nil
+}
+
+// Save 覆盖保存平台的全部Cookie
+func (s *FileCookieStore) Save(platform Platform, cookies []*Cookie) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.load()
+	if err != nil {
+		return err
+	}
+	updated := make(map[Platform][]*Cookie, len(all)+1)
+	for p, c := range all {
+		updated[p] = c
+	}
+	updated[platform] = cloneCookies(cookies)
+
+	data, err := json.MarshalIndent(updated, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode cookie file: %w", err)
+	}
+	if err := writeFileAtomic(s.path, data, 0o600); err != nil {
+		// 写入失败时丢弃缓存，下次从文件重新读取
+		s.cache = nil
+		return err
+	}
+	s.cache = updated
+	return nil
+}
+
+// load 返回缓存的文件内容，未缓存时读取文件（调用方需持有锁）
+func (s *FileCookieStore) load() (map[Platform][]*Cookie, error) {
+	if s.cache != nil {
+		return s.cache, nil
+	}
+	all, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	s.cache = all
+	return all, nil
+}
+
+// read 读取文件内容，文件不存在时返回空集合
+func (s *FileCookieStore) read() (map[Platform][]*Cookie, error) {
+	all := make(map[Platform][]*Cookie)
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return all, nil
+		}
+		return nil, fmt.Errorf("read cookie file: %w", err)
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return all, nil
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("decode cookie file: %w", err)
+	}
+	return all, nil
+}
+
+// CookieExpiry 单个Cookie的过期信息
+type CookieExpiry struct {
+	Name    string    `json:"name"`    // 名称
+	Domain  string    `json:"domain"`  // 域名
+	Expires time.Time `json:"expires"` // 过期时间，零值表示会话Cookie
+	Expired bool      `json:"expired"` // 是否已过期
+}
+
+// CookieStatus 平台Cookie状态
+type CookieStatus struct {
+	Platform       Platform       `json:"platform"`        // 平台
+	Total          int            `json:"total"`           // Cookie总数
+	Expired        int            `json:"expired"`         // 已过期数量
+	EarliestExpiry time.Time      `json:"earliest_expiry"` // 未过期Cookie中最早的过期时间
+	Cookies        []CookieExpiry `json:"cookies"`         // 各Cookie的过期信息
+}
+
+// CookieJar 按平台管理Cookie
+//
+// 导入的Cookie按平台域名过滤；解析请求未提供Cookie时由SDK自动附加，
+// 平台返回的Set-Cookie会合并回存储中。
+type CookieJar struct {
+	mu    sync.Mutex
+	store CookieStore
+}
+
+// NewCookieJar 创建Cookie管理器，store为nil时使用内存存储
+func NewCookieJar(store CookieStore) *CookieJar {
+	if store == nil {
+		store = NewMemoryCookieStore()
+	}
+	return &CookieJar{store: store}
+}
+
+// Cookies 获取平台的全部Cookie（包含已过期的）
+func (j *CookieJar) Cookies(platform Platform) ([]*Cookie, error) {
+	return j.store.Load(platform)
+}
+
+// Import 导入Cookie，仅保留属于该平台域名的Cookie，返回导入数量
+func (j *CookieJar) Import(platform Platform, cookies []*Cookie) (int, error) {
+	var matched []*Cookie
+	for _, c := range cookies {
+		if c.Name != "" && CookieDomainMatches(platform, c.Domain) {
+			matched = append(matched, c)
+		}
+	}
+	if len(matched) == 0 {
+		return 0, nil
+	}
+
+	return len(matched), j.merge(platform, matched, nil)
+}
+
+// ImportNetscape 导入Netscape格式的cookies.txt，返回导入数量
+func (j *CookieJar) ImportNetscape(platform Platform, r io.Reader) (int, error) {
+	cookies, err := ParseNetscapeCookies(r)
+	if err != nil {
+		return 0, err
+	}
+	return j.Import(platform, cookies)
+}
+
+// ImportBrowserJSON 导入浏览器扩展或Playwright导出的JSON格式Cookie，返回导入数量
+func (j *CookieJar) ImportBrowserJSON(platform Platform, r io.Reader) (int, error) {
+	cookies, err := ParseBrowserJSONCookies(r)
+	if err != nil {
+		return 0, err
+	}
+	return j.Import(platform, cookies)
+}
+
+// Header 生成平台未过期Cookie的Cookie请求头，没有可用Cookie时返回空字符串
+func (j *CookieJar) Header(platform Platform) (string, error) {
+	cookies, err := j.store.Load(platform)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	pairs := make([]string, 0, len(cookies))
+	for _, c := range cookies {
+		if !c.Expired(now) {
+			pairs = append(pairs, c.Name+"="+c.Value)
+		}
+	}
+	return strings.Join(pairs, "; "), nil
+}
+
+// Update 合并平台返回的Set-Cookie，Max-Age<0或已过期的Cookie会被删除
+func (j *CookieJar) Update(platform Platform, setCookies []*http.Cookie) error {
+	if len(setCookies) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var updates, removals []*Cookie
+	for _, hc := range setCookies {
+		c := &Cookie{
+			Name:     hc.Name,
+			Value:    hc.Value,
+			Domain:   hc.Domain,
+			Path:     hc.Path,
+			Expires:  hc.Expires,
+			Secure:   hc.Secure,
+			HTTPOnly: hc.HttpOnly,
+		}
+		if c.Domain == "" {
+			c.Domain = defaultCookieDomain(platform)
+		}
+		if c.Path == "" {
+			c.Path = "/"
+		}
+		if hc.MaxAge > 0 {
+			c.Expires = now.Add(time.Duration(hc.MaxAge) * time.Second)
+		}
+
+		if hc.MaxAge < 0 || c.Expired(now) {
+			removals = append(removals, c)
+		} else {
+			updates = append(updates, c)
+		}
+	}
+
+	return j.merge(platform, updates, removals)
+}
+
+// Status 获取平台Cookie的过期状态
+func (j *CookieJar) Status(platform Platform) (*CookieStatus, error) {
+	cookies, err := j.store.Load(platform)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	status := &CookieStatus{Platform: platform, Total: len(cookies)}
+	for _, c := range cookies {
+		expired := c.Expired(now)
+		if expired {
+			status.Expired++
+		} else if !c.Expires.IsZero() && (status.EarliestExpiry.IsZero() || c.Expires.Before(status.EarliestExpiry)) {
+			status.EarliestExpiry = c.Expires
+		}
+		status.Cookies = append(status.Cookies, CookieExpiry{
+			Name:    c.Name,
+			Domain:  c.Domain,
+			Expires: c.Expires,
+			Expired: expired,
+		})
+	}
+
+	return status, nil
+}
+
+// merge 按名称、域名和路径合并Cookie
+func (j *CookieJar) merge(platform Platform, updates, removals []*Cookie) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	existing, err := j.store.Load(platform)
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(existing))
+	for i, c := range existing {
+		index[c.key()] = i
+	}
+
+	for _, c := range updates {
+		if i, ok := index[c.key()]; ok {
+			existing[i] = c
+			continue
+		}
+		index[c.key()] = len(existing)
+		existing = append(existing, c)
+	}
+
+	if len(removals) > 0 {
+		removed := make(map[string]bool, len(removals))
+		for _, c := range removals {
+			removed[c.key()] = true
+		}
+		kept := existing[:0]
+		for _, c := range existing {
+			if !removed[c.key()] {
+				kept = append(kept, c)
+			}
+		}
+		existing = kept
+	}
+
+	sort.SliceStable(existing, func(a, b int) bool { return existing[a].Name < existing[b].Name })
+	return j.store.Save(platform, existing)
+}
+
+// CookieDomainMatches Cookie域名是否属于指定平台
+func CookieDomainMatches(platform Platform, domain string) bool {
+	domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if domain == "" {
+		return false
+	}
+
+	for _, h := range platformHosts {
+		if h.platform == platform && (domain == h.suffix || strings.HasSuffix(domain, "."+h.suffix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultCookieDomain 平台的默认Cookie域名
+func defaultCookieDomain(platform Platform) string {
+	for _, h := range platformHosts {
+		if h.platform == platform {
+			return "." + h.suffix
+		}
+	}
+	return ""
+}
+
+// ParseNetscapeCookies 解析Netscape格式的cookies.txt
+func ParseNetscapeCookies(r io.Reader) ([]*Cookie, error) {
+	var cookies []*Cookie
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r\n")
+
+		httpOnly := false
+		if strings.HasPrefix(line, "#HttpOnly_") {
+			httpOnly = true
+			line = strings.TrimPrefix(line, "#HttpOnly_")
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 7 {
+			return nil, fmt.Errorf("invalid cookies.txt line %d: expected 7 tab-separated fields", lineNo)
+		}
+
+		c := &Cookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    strings.Join(fields[6:], "\t"),
+			HTTPOnly: httpOnly,
+		}
+		if expires, err := strconv.ParseInt(fields[4], 10, 64); err == nil && expires > 0 {
+			c.Expires = time.Unix(expires, 0)
+		}
+		cookies = append(cookies, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read cookies.txt: %w", err)
+	}
+
+	return cookies, nil
+}
+
+// browserCookie 浏览器扩展（EditThisCookie、Cookie-Editor等）和Playwright导出的Cookie
+type browserCookie struct {
+	Name           string          `json:"name"`
+	Value          string          `json:"value"`
+	Domain         string          `json:"domain"`
+	Path           string          `json:"path"`
+	Secure         bool            `json:"secure"`
+	HTTPOnly       bool            `json:"httpOnly"`
+	Session        bool            `json:"session"`
+	ExpirationDate float64         `json:"expirationDate"`
+	Expires        json.RawMessage `json:"expires"`
+}
+
+// ParseBrowserJSONCookies 解析浏览器导出的JSON格式Cookie
+//
+// 支持Cookie数组以及Playwright storageState格式（{"cookies": [...]}）。
+func ParseBrowserJSONCookies(r io.Reader) ([]*Cookie, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read cookie json: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+
+	var raw []browserCookie
+	if len(data) > 0 && data[0] == '{' {
+		var state struct {
+			Cookies []browserCookie `json:"cookies"`
+		}
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, fmt.Errorf("decode cookie json: %w", err)
+		}
+		raw = state.Cookies
+	} else if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("decode cookie json: %w", err)
+	}
+
+	cookies := make([]*Cookie, 0, len(raw))
+	for _, bc := range raw {
+		c := &Cookie{
+			Name:     bc.Name,
+			Value:    bc.Value,
+			Domain:   bc.Domain,
+			Path:     bc.Path,
+			Secure:   bc.Secure,
+			HTTPOnly: bc.HTTPOnly,
+		}
+		if !bc.Session {
+			switch {
+			case bc.ExpirationDate > 0:
+				c.Expires = ParseUnixTime(bc.ExpirationDate)
+			case len(bc.Expires) > 0:
+				c.Expires = parseJSONExpires(bc.Expires)
+			}
+		}
+		cookies = append(cookies, c)
+	}
+
+	return cookies, nil
+}
+
+// parseJSONExpires 解析数字时间戳或时间字符串形式的过期时间
+func parseJSONExpires(raw json.RawMessage) time.Time {
+	var ts float64
+	if err := json.Unmarshal(raw, &ts); err == nil {
+		return ParseUnixTime(ts)
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		if t, err := http.ParseTime(text); err == nil {
+			return t
+		}
+		return ParseTime(text)
+	}
+
+	return time.Time{}
+}
+
+// cookieRecorderKey 上下文中Set-Cookie记录器的键
+type cookieRecorderKey struct{}
+
+// cookieRecorder 收集解析过程中平台返回的Set-Cookie
+type cookieRecorder struct {
+	mu      sync.Mutex
+	cookies []*http.Cookie
+}
+
+// withCookieRecorder 在上下文中附加Set-Cookie记录器
+func withCookieRecorder(ctx context.Context) (context.Context, *cookieRecorder) {
+	recorder := &cookieRecorder{}
+	return context.WithValue(ctx, cookieRecorderKey{}, recorder), recorder
+}
+
+// ReportSetCookies 供解析器上报平台返回的Set-Cookie，SDK配置了CookieJar时会合并保存
+//
+// 内置解析器只与后端服务通信，拿不到平台下发的Set-Cookie，不会调用本函数；
+// 它用于直接请求平台的自定义解析器。内置解析器的Cookie需要通过CookieJar.Import等方法更新。
+func ReportSetCookies(ctx context.Context, cookies []*http.Cookie) {
+	if len(cookies) == 0 {
+		return
+	}
+
+	recorder, ok := ctx.Value(cookieRecorderKey{}).(*cookieRecorder)
+	if !ok {
+		return
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.cookies = append(recorder.cookies, cookies...)
+}
+
+// CookieID 生成Cookie的不可逆标识，与日志中的cookie_id一致，用于在需要保存或展示时代替Cookie原文
+func CookieID(cookie string) string {
+	return cookieKey(cookie)
+}
+
+// cookieKey 生成Cookie的标识，用于按Cookie分配代理和限流，避免在内存中以明文Cookie作为键
+func cookieKey(cookie string) string {
+	if cookie == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(cookie))
+	return hex.EncodeToString(sum[:8])
+}
+
+// cloneCookies 深拷贝Cookie列表
+func cloneCookies(cookies []*Cookie) []*Cookie {
+	if cookies == nil {
+		return nil
+	}
+	cloned := make([]*Cookie, len(cookies))
+	for i, c := range cookies {
+		copied := *c
+		cloned[i] = &copied
+	}
+	return cloned
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免写入中断导致文件损坏
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("chmod temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,302 @@
+package videosdk
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CookieStrategy Cookie池的轮换策略
+type CookieStrategy string
+
+const (
+	CookieStrategyRoundRobin CookieStrategy = "round_robin"         // 轮询
+	CookieStrategyLRU        CookieStrategy = "least_recently_used" // 最久未使用优先
+	CookieStrategyWeighted   CookieStrategy = "weighted"            // 按权重平滑轮询
+)
+
+// pooledCookie Cookie池中的单个账号
+type pooledCookie struct {
+	id     string
+	cookie string
+	weight int
+
+	currentWeight       int // 平滑加权轮询的当前权重
+	uses                int64
+	successes           int64
+	failures            int64
+	consecutiveFailures int
+	lastUsed            time.Time
+	lastError           string
+	cooldownUntil       time.Time
+}
+
+// healthy 账号在指定时间是否可用
+func (c *pooledCookie) healthy(now time.Time) bool {
+	return !now.Before(c.cooldownUntil)
+}
+
+// CookieLease 从Cookie池中取出的Cookie
+type CookieLease struct {
+	Platform Platform // 平台
+	ID       string   // 账号标识
+	Cookie   string   // Cookie值
+}
+
+// PooledCookieStatus Cookie池中单个账号的状态
+type PooledCookieStatus struct {
+	ID                  string    `json:"id"`                   // 账号标识
+	Weight              int       `json:"weight"`               // 权重
+	Healthy             bool      `json:"healthy"`              // 当前是否可用
+	Uses                int64     `json:"uses"`                 // 使用次数
+	Successes           int64     `json:"successes"`            // 成功次数
+	Failures            int64     `json:"failures"`             // 失效次数
+	ConsecutiveFailures int       `json:"consecutive_failures"` // 连续失效次数
+	LastUsed            time.Time `json:"last_used"`            // 最近使用时间
+	LastError           string    `json:"last_error,omitempty"` // 最近一次失效原因
+	CooldownUntil       time.Time `json:"cooldown_until"`       // 冷却结束时间
+}
+
+// CookiePoolStatus 平台Cookie池状态
+type CookiePoolStatus struct {
+	Platform Platform             `json:"platform"` // 平台
+	Strategy CookieStrategy       `json:"strategy"` // 轮换策略
+	Total    int                  `json:"total"`    // 账号总数
+	Healthy  int                  `json:"healthy"`  // 可用账号数
+	Cookies  []PooledCookieStatus `json:"cookies"`  // 各账号状态
+}
+
+// CookiePool 多账号Cookie池
+//
+// 每个平台维护一组账号Cookie，按策略轮换使用。解析器返回需要登录或验证的错误时，
+// 对应Cookie进入冷却期，连续失效时冷却时间成倍增加（不超过MaxCooldown）。
+type CookiePool struct {
+	mu          sync.Mutex
+	strategy    CookieStrategy
+	cooldown    time.Duration
+	maxCooldown time.Duration
+	cookies     map[Platform][]*pooledCookie
+	next        map[Platform]int
+}
+
+// NewCookiePool 创建Cookie池
+func NewCookiePool(strategy CookieStrategy) *CookiePool {
+	if strategy == "" {
+		strategy = CookieStrategyRoundRobin
+	}
+	return &CookiePool{
+		strategy:    strategy,
+		cooldown:    10 * time.Minute,
+		maxCooldown: 6 * time.Hour,
+		cookies:     make(map[Platform][]*pooledCookie),
+		next:        make(map[Platform]int),
+	}
+}
+
+// SetCooldown 设置失效Cookie的初始冷却时间和最长冷却时间
+func (p *CookiePool) SetCooldown(cooldown, maxCooldown time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cooldown = cooldown
+	p.maxCooldown = maxCooldown
+}
+
+// Add 添加或更新账号Cookie，weight<=0时按1处理
+func (p *CookiePool) Add(platform Platform, id, cookie string, weight int) error {
+	if id == "" {
+		return fmt.Errorf("cookie id cannot be empty")
+	}
+	if cookie == "" {
+		return fmt.Errorf("cookie cannot be empty")
+	}
+	if weight <= 0 {
+		weight = 1
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, c := range p.cookies[platform] {
+		if c.id == id {
+			c.cookie = cookie
+			c.weight = weight
+			return nil
+		}
+	}
+
+	p.cookies[platform] = append(p.cookies[platform], &pooledCookie{id: id, cookie: cookie, weight: weight})
+	return nil
+}
+
+// Remove 移除账号Cookie
+func (p *CookiePool) Remove(platform Platform, id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cookies := p.cookies[platform]
+	for i, c := range cookies {
+		if c.id == id {
+			p.cookies[platform] = append(cookies[:i:i], cookies[i+1:]...)
+			return
+		}
+	}
+}
+
+// Len 平台的账号数量
+func (p *CookiePool) Len(platform Platform) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.cookies[platform])
+}
+
+// Acquire 按策略取出一个可用Cookie，没有可用Cookie时返回false
+func (p *CookiePool) Acquire(platform Platform) (*CookieLease, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var healthy []*pooledCookie
+	for _, c := range p.cookies[platform] {
+		if c.healthy(now) {
+			healthy = append(healthy, c)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil, false
+	}
+
+	var chosen *pooledCookie
+	switch p.strategy {
+	case CookieStrategyLRU:
+		chosen = healthy[0]
+		for _, c := range healthy[1:] {
+			if c.lastUsed.Before(chosen.lastUsed) {
+				chosen = c
+			}
+		}
+	case CookieStrategyWeighted:
+		total := 0
+		for _, c := range healthy {
+			c.currentWeight += c.weight
+			total += c.weight
+			if chosen == nil || c.currentWeight > chosen.currentWeight {
+				chosen = c
+			}
+		}
+		chosen.currentWeight -= total
+	default:
+		index := p.next[platform] % len(healthy)
+		p.next[platform] = index + 1
+		chosen = healthy[index]
+	}
+
+	chosen.uses++
+	chosen.lastUsed = now
+	return &CookieLease{Platform: platform, ID: chosen.id, Cookie: chosen.cookie}, true
+}
+
+// ReportSuccess 记录Cookie使用成功，重置连续失效次数
+func (p *CookiePool) ReportSuccess(lease *CookieLease) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c := p.find(lease); c != nil {
+		c.successes++
+		c.consecutiveFailures = 0
+	}
+}
+
+// ReportFailure 记录Cookie失效（需要登录或验证），使其进入冷却期
+func (p *CookiePool) ReportFailure(lease *CookieLease, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c := p.find(lease)
+	if c == nil {
+		return
+	}
+
+	c.failures++
+	c.consecutiveFailures++
+	if err != nil {
+		c.lastError = err.Error()
+	}
+
+	cooldown := p.cooldown
+	for i := 1; i < c.consecutiveFailures && cooldown < p.maxCooldown; i++ {
+		cooldown *= 2
+	}
+	if p.maxCooldown > 0 && cooldown > p.maxCooldown {
+		cooldown = p.maxCooldown
+	}
+	c.cooldownUntil = time.Now().Add(cooldown)
+}
+
+// Reset 立即结束Cookie的冷却期（例如重新登录后）
+func (p *CookiePool) Reset(platform Platform, id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c := p.find(&CookieLease{Platform: platform, ID: id}); c != nil {
+		c.cooldownUntil = time.Time{}
+		c.consecutiveFailures = 0
+		c.lastError = ""
+	}
+}
+
+// Status 获取平台Cookie池状态
+func (p *CookiePool) Status(platform Platform) *CookiePoolStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	status := &CookiePoolStatus{
+		Platform: platform,
+		Strategy: p.strategy,
+		Total:    len(p.cookies[platform]),
+	}
+	for _, c := range p.cookies[platform] {
+		healthy := c.healthy(now)
+		if healthy {
+			status.Healthy++
+		}
+		status.Cookies = append(status.Cookies, PooledCookieStatus{
+			ID:                  c.id,
+			Weight:              c.weight,
+			Healthy:             healthy,
+			Uses:                c.uses,
+			Successes:           c.successes,
+			Failures:            c.failures,
+			ConsecutiveFailures: c.consecutiveFailures,
+			LastUsed:            c.lastUsed,
+			LastError:           c.lastError,
+			CooldownUntil:       c.cooldownUntil,
+		})
+	}
+	sort.Slice(status.Cookies, func(i, j int) bool { return status.Cookies[i].ID < status.Cookies[j].ID })
+
+	return status
+}
+
+// find 查找租约对应的账号
+func (p *CookiePool) find(lease *CookieLease) *pooledCookie {
+	if lease == nil {
+		return nil
+	}
+	for _, c := range p.cookies[lease.Platform] {
+		if c.id == lease.ID {
+			return c
+		}
+	}
+	return nil
+}
+
+// IsCookieFailure 错误是否表示Cookie失效（需要登录或验证）
+func IsCookieFailure(err error) bool {
+	switch ErrorCodeOf(err) {
+	case ErrCodeLoginRequired, ErrCodeVerificationRequired:
+		return true
+	}
+	return false
+}
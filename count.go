@@ -0,0 +1,107 @@
+package videosdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Count 统计数量
+//
+// 平台隐藏或未返回的数量使用CountUnknown表示，与真实的0区分开，
+// JSON序列化时未知数量输出为null。
+type Count int64
+
+// CountUnknown 未知或被隐藏的数量
+const CountUnknown Count = -1
+
+// countUnits 中文及常见缩写数量单位
+var countUnits = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"亿", 1e8},
+	{"万", 1e4},
+	{"千", 1e3},
+	{"w", 1e4},
+	{"W", 1e4},
+	{"k", 1e3},
+	{"K", 1e3},
+	{"m", 1e6},
+	{"M", 1e6},
+}
+
+// ParseCount 解析平台返回的数量文本
+//
+// 支持"1234"、"1,234"、"1.2w"、"203.7万"、"3亿"、"10万+"等格式；
+// 空值、"-"等隐藏数量以及无法识别的文本返回CountUnknown。
+func ParseCount(value string) Count {
+	value = strings.TrimSpace(value)
+	value = strings.TrimSuffix(value, "+")
+	value = strings.ReplaceAll(value, ",", "")
+	value = strings.ReplaceAll(value, "，", "")
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return CountUnknown
+	}
+
+	multiplier := 1.0
+	for _, unit := range countUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			multiplier = unit.multiplier
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			break
+		}
+	}
+
+	num, err := strconv.ParseFloat(value, 64)
+	if err != nil || num < 0 || math.IsNaN(num) || math.IsInf(num, 0) {
+		return CountUnknown
+	}
+
+	return Count(math.Round(num * multiplier))
+}
+
+// Known 数量是否已知
+func (c Count) Known() bool {
+	return c >= 0
+}
+
+// Int64 获取数量，未知时返回0
+func (c Count) Int64() int64 {
+	if !c.Known() {
+		return 0
+	}
+	return int64(c)
+}
+
+// MarshalJSON 未知数量序列化为null
+func (c Count) MarshalJSON() ([]byte, error) {
+	if !c.Known() {
+		return []byte("null"), nil
+	}
+	return strconv.AppendInt(nil, int64(c), 10), nil
+}
+
+// UnmarshalJSON 支持数字、数量文本以及null
+func (c *Count) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*c = CountUnknown
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		*c = ParseCount(text)
+		return nil
+	}
+
+	*c = ParseCount(string(data))
+	return nil
+}
@@ -0,0 +1,77 @@
+package videosdk
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Count
+	}{
+		{"1234", 1234},
+		{"0", 0},
+		{"1,234", 1234},
+		{"1，234", 1234},
+		{"1.2w", 12000},
+		{"1.2W", 12000},
+		{"203.7万", 2037000},
+		{"3亿", 300000000},
+		{"10万+", 100000},
+		{"1.2k", 1200},
+		{" 5千 ", 5000},
+		{"-", CountUnknown},
+		{"", CountUnknown},
+		{"  ", CountUnknown},
+		{"-5", CountUnknown},
+		{"-1.2w", CountUnknown},
+		{"NaN", CountUnknown},
+		{"Inf", CountUnknown},
+		{"很多", CountUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := ParseCount(tt.value); got != tt.want {
+			t.Errorf("ParseCount(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+
+	// 未知数量与真实的0必须区分
+	if zero, unknown := ParseCount("0"), ParseCount("-"); zero == unknown || !zero.Known() || unknown.Known() {
+		t.Errorf("ParseCount(\"0\") = %d, ParseCount(\"-\") = %d, want distinct known 0 and unknown", zero, unknown)
+	}
+}
+
+func TestCountJSON(t *testing.T) {
+	tests := []struct {
+		count Count
+		want  string
+	}{
+		{0, "0"},
+		{12000, "12000"},
+		{CountUnknown, "null"},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.count)
+		if err != nil || string(data) != tt.want {
+			t.Errorf("Marshal(%d) = %s, %v; want %s", tt.count, data, err, tt.want)
+		}
+
+		var decoded Count
+		if err := json.Unmarshal(data, &decoded); err != nil || decoded != tt.count {
+			t.Errorf("Unmarshal(%s) = %d, %v; want %d", data, decoded, err, tt.count)
+		}
+	}
+
+	var stats struct {
+		Like  Count `json:"like"`
+		Share Count `json:"share"`
+	}
+	if err := json.Unmarshal([]byte(`{"like": "1.2w", "share": null}`), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Like != 12000 || stats.Share != CountUnknown {
+		t.Errorf("decoded %+v, want like 12000 and unknown share", stats)
+	}
+}
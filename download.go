@@ -0,0 +1,220 @@
+package videosdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// platformReferers 各平台媒体CDN要求的Referer
+var platformReferers = map[Platform]string{
+	PlatformDouyin:      "https://www.douyin.com/",
+	PlatformKuaishou:    "https://www.kuaishou.com/",
+	PlatformXiaohongshu: "https://www.xiaohongshu.com/",
+	PlatformBilibili:    "https://www.bilibili.com/",
+}
+
+// mediaExtensions 已知的媒体文件扩展名
+var mediaExtensions = map[string]bool{
+	".mp4": true, ".mov": true, ".webm": true, ".m4a": true, ".mp3": true,
+	".jpg": true, ".jpeg": true, ".png": true, ".webp": true, ".gif": true, ".heic": true,
+}
+
+// Downloader 媒体文件下载器
+type Downloader struct {
+	client    *http.Client
+	mu        sync.RWMutex
+	userAgent string
+	proxyPool *ProxyPool
+}
+
+// NewDownloader 创建媒体文件下载器
+func NewDownloader() *Downloader {
+	return &Downloader{
+		client:    &http.Client{Timeout: 10 * time.Minute},
+		userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36",
+	}
+}
+
+// SetTimeout 设置单个文件下载的超时时间
+func (d *Downloader) SetTimeout(timeout time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.client = &http.Client{Timeout: timeout}
+}
+
+// SetUserAgent 设置User-Agent
+func (d *Downloader) SetUserAgent(userAgent string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.userAgent = userAgent
+}
+
+// SetProxyPool 设置代理池，下载请求将轮换使用池中的代理
+func (d *Downloader) SetProxyPool(pool *ProxyPool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.proxyPool = pool
+}
+
+// Open 打开媒体文件的下载流，调用方负责关闭返回的响应体
+func (d *Downloader) Open(ctx context.Context, platform Platform, item DownloadItem) (*http.Response, error) {
+	if item.URL == "" {
+		return nil, NewError(ErrCodeInvalidRequest, fmt.Errorf("download url is empty"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, item.URL, nil)
+	if err != nil {
+		return nil, NewError(ErrCodeInvalidRequest, fmt.Errorf("invalid download url: %w", err))
+	}
+
+	d.mu.RLock()
+	req.Header.Set("User-Agent", d.userAgent)
+	client := d.client
+	proxyPool := d.proxyPool
+	d.mu.RUnlock()
+
+	if proxyPool != nil {
+		if proxy, ok := proxyPool.Acquire(""); ok {
+			proxyClient, err := proxyPool.Client(proxy, client.Timeout)
+			if err != nil {
+				return nil, NewError(ErrCodeProxyFailed, err)
+			}
+			client = proxyClient
+		}
+	}
+
+	if referer, ok := platformReferers[platform]; ok {
+		req.Header.Set("Referer", referer)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, NewError(ErrCodeDownloadFailed, fmt.Errorf("download request failed: %w", err))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, NewError(ErrCodeDownloadFailed, fmt.Errorf("download failed, status code: %d", resp.StatusCode))
+	}
+
+	return resp, nil
+}
+
+// DownloadFile 下载单个媒体文件到指定目录，返回保存的文件路径
+//
+// 文件名为name加上根据URL、Content-Type或媒体类型推断的扩展名。
+func (d *Downloader) DownloadFile(ctx context.Context, platform Platform, item DownloadItem, dir, name string) (string, error) {
+	resp, err := d.Open(ctx, platform, item)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", NewError(ErrCodeDownloadFailed, fmt.Errorf("create directory failed: %w", err))
+	}
+
+	filePath := filepath.Join(dir, name+mediaExtension(item, resp.Header.Get("Content-Type")))
+
+	// 先写入临时文件，完整下载后再重命名，避免留下不完整的文件
+	tmp, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		return "", NewError(ErrCodeDownloadFailed, fmt.Errorf("create file failed: %w", err))
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return "", NewError(ErrCodeDownloadFailed, fmt.Errorf("write file failed: %w", err))
+	}
+	if err := tmp.Close(); err != nil {
+		return "", NewError(ErrCodeDownloadFailed, fmt.Errorf("write file failed: %w", err))
+	}
+
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return "", NewError(ErrCodeDownloadFailed, fmt.Errorf("save file failed: %w", err))
+	}
+
+	return filePath, nil
+}
+
+// Download 下载作品的全部媒体文件到指定目录，返回保存的文件路径
+//
+// 文件命名为"{平台}_{作品ID}_{序号}"；部分文件下载失败时返回已保存的文件路径和第一个错误。
+func (d *Downloader) Download(ctx context.Context, info *VideoInfo, dir string) ([]string, error) {
+	if info == nil {
+		return nil, NewError(ErrCodeInvalidRequest, fmt.Errorf("video info cannot be nil"))
+	}
+
+	var files []string
+	var firstErr error
+	for i, item := range info.Downloads {
+		name := fmt.Sprintf("%s_%s_%d", info.Platform, sanitizeFileName(info.ID), i+1)
+		filePath, err := d.DownloadFile(ctx, info.Platform, item, dir, name)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("download item %d failed: %w", i+1, err)
+			}
+			continue
+		}
+		files = append(files, filePath)
+	}
+
+	return files, firstErr
+}
+
+// mediaExtension 推断媒体文件扩展名
+func mediaExtension(item DownloadItem, contentType string) string {
+	if ext := strings.ToLower(path.Ext(strings.SplitN(item.URL, "?", 2)[0])); mediaExtensions[ext] {
+		return ext
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mediaType {
+		case "video/mp4":
+			return ".mp4"
+		case "image/jpeg":
+			return ".jpg"
+		case "image/png":
+			return ".png"
+		case "image/webp":
+			return ".webp"
+		case "image/gif":
+			return ".gif"
+		case "image/heic":
+			return ".heic"
+		}
+	}
+
+	switch item.Type {
+	case MediaTypeImage:
+		return ".jpg"
+	case MediaTypeGif:
+		return ".gif"
+	default:
+		return ".mp4"
+	}
+}
+
+// sanitizeFileName 替换文件名中的非法字符
+func sanitizeFileName(name string) string {
+	if name == "" {
+		return "unknown"
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, name)
+}
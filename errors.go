@@ -0,0 +1,101 @@
+package videosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrorCode 错误类别
+type ErrorCode string
+
+const (
+	ErrCodeInvalidRequest       ErrorCode = "invalid_request"       // 请求参数错误
+	ErrCodeUnsupportedPlatform  ErrorCode = "unsupported_platform"  // 平台不支持
+	ErrCodeParseFailed          ErrorCode = "parse_failed"          // 解析失败
+	ErrCodeTimeout              ErrorCode = "timeout"               // 请求超时
+	ErrCodeCanceled             ErrorCode = "canceled"              // 请求被取消
+	ErrCodeDownloadFailed       ErrorCode = "download_failed"       // 下载失败
+	ErrCodeUnauthorized         ErrorCode = "unauthorized"          // 未授权访问
+	ErrCodeLoginRequired        ErrorCode = "login_required"        // Cookie失效，平台要求登录
+	ErrCodeVerificationRequired ErrorCode = "verification_required" // 平台要求验证（验证码、滑块等）
+	ErrCodeProxyFailed          ErrorCode = "proxy_failed"          // 代理不可用
+	ErrCodeRateLimited          ErrorCode = "rate_limited"          // 触发限流
+	ErrCodeBackendUnavailable   ErrorCode = "backend_unavailable"   // 后端服务不可用（连接失败、5xx或全部熔断）
+	ErrCodeSchemaMismatch       ErrorCode = "schema_mismatch"       // 后端响应结构与期望不符（严格模式）
+	ErrCodeTokenRequired        ErrorCode = "token_required"        // 作品需要访问令牌（如小红书的xsec_token），令牌缺失或已过期
+)
+
+// Error 带错误类别的SDK错误
+type Error struct {
+	Code ErrorCode // 错误类别
+	Err  error     // 原始错误
+}
+
+// NewError 创建带错误类别的SDK错误
+func NewError(code ErrorCode, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+// Error 实现error接口
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return string(e.Code)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap 返回原始错误
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCodeOf 获取错误类别
+//
+// 错误链中没有*Error时，超时和取消分别归类为ErrCodeTimeout和ErrCodeCanceled，
+// 其余归类为ErrCodeParseFailed；err为nil时返回空字符串。
+func ErrorCodeOf(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+
+	// 超时和取消优先于外层包装的类别
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrCodeTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrCodeCanceled
+	}
+
+	var sdkErr *Error
+	if errors.As(err, &sdkErr) {
+		return sdkErr.Code
+	}
+
+	return ErrCodeParseFailed
+}
+
+// TokenError 作品链接缺少访问令牌或令牌已过期，通过ErrCodeTokenRequired错误返回
+type TokenError struct {
+	Platform Platform // 平台
+	VideoID  string   // 作品ID，未知时为空
+	Param    string   // 令牌参数名，如xsec_token
+	Expired  bool     // 请求带有令牌但已失效；为false时表示缺少令牌
+	Message  string   // 后端返回的消息
+}
+
+// Error 实现error接口
+func (e *TokenError) Error() string {
+	state := "missing"
+	if e.Expired {
+		state = "expired"
+	}
+	msg := fmt.Sprintf("%s %s is %s", e.Platform, e.Param, state)
+	if e.VideoID != "" {
+		msg += " for " + e.VideoID
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
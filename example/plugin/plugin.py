@@ -0,0 +1,96 @@
+#!/usr/bin/env python3
+"""videosdk外部解析器插件示例（标准输入输出协议，协议版本1）
+
+每行读取一个JSON请求，向标准输出写一行JSON响应；日志写到标准错误。
+这里用固定数据模拟一个名为example的平台，实际插件在parse_video中请求目标网站即可。
+
+    parser, err := parsers.NewPluginParser(parsers.PluginConfig{
+        Command: []string{"python3", "example/plugin/plugin.py"},
+    })
+"""
+import json
+import re
+import sys
+
+PLATFORM = "example"
+ID_PATTERN = re.compile(r"https?://example\.com/v/(\w+)")
+
+
+class PluginError(Exception):
+    def __init__(self, code, message):
+        super().__init__(message)
+        self.code = code
+
+
+def hello(params):
+    return {"platform": PLATFORM, "protocol": 1}
+
+
+def extract_video_id(params):
+    match = ID_PATTERN.search(params.get("url", ""))
+    if not match:
+        raise PluginError("invalid_request", "无法从URL中提取视频ID")
+    return {"video_id": match.group(1)}
+
+
+def validate_request(params):
+    if not params.get("url") and not params.get("video_id"):
+        raise PluginError("invalid_request", "video_id 或 url 至少需要提供一个")
+    return {}
+
+
+def parse_video(params):
+    request = params["request"]
+    video_id = request.get("video_id") or extract_video_id(request)["video_id"]
+    if video_id == "private":
+        raise PluginError("login_required", "作品仅登录后可见")
+    return {
+        "id": video_id,
+        "title": "示例作品 " + video_id,
+        "type": "video",
+        "url": "https://example.com/v/" + video_id,
+        "create_time": "2024-09-10T18:22:41+08:00",
+        "duration": 15000000000,
+        "downloads": [{"url": "https://cdn.example.com/%s.mp4" % video_id, "type": "video"}],
+        "author": {"uid": "42", "nickname": "示例作者"},
+        # 未知的统计数量可以省略或返回null
+        "stats": {"like_count": 128, "play_count": None},
+        "extra": {"source": "plugin"},
+    }
+
+
+METHODS = {
+    "hello": hello,
+    "extract_video_id": extract_video_id,
+    "validate_request": validate_request,
+    "parse_video": parse_video,
+}
+
+
+def main():
+    for line in sys.stdin:
+        if not line.strip():
+            continue
+        request = json.loads(line)
+        method = request.get("method")
+        if method == "cancel":
+            # 通知无需响应，这个示例的请求都是同步完成的
+            continue
+
+        response = {"id": request.get("id")}
+        try:
+            handler = METHODS.get(method)
+            if handler is None:
+                raise PluginError("invalid_request", "unknown method: %s" % method)
+            response["result"] = handler(request.get("params") or {})
+        except PluginError as e:
+            response["error"] = {"code": e.code, "message": str(e)}
+        except Exception as e:  # noqa: BLE001
+            response["error"] = {"code": "parse_failed", "message": str(e)}
+
+        sys.stdout.write(json.dumps(response, ensure_ascii=False) + "\n")
+        sys.stdout.flush()
+
+
+if __name__ == "__main__":
+    main()
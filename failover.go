@@ -0,0 +1,331 @@
+package videosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BackendStatus 故障转移解析器中单个后端的状态
+type BackendStatus struct {
+	Backend             string       `json:"backend"`              // 后端服务地址
+	State               CircuitState `json:"state"`                // 熔断器状态
+	ConsecutiveFailures int          `json:"consecutive_failures"` // 连续失败次数
+	LastError           string       `json:"last_error,omitempty"` // 最近一次失败原因
+}
+
+// failoverBackend 故障转移解析器中的单个后端
+type failoverBackend struct {
+	name    string
+	parser  Parser
+	breaker *CircuitBreaker
+}
+
+// attemptResult 单次后端请求的结果
+type attemptResult struct {
+	info *VideoInfo
+	err  error
+}
+
+// FailoverParser 同一平台多后端的故障转移解析器
+//
+// 按添加顺序依次尝试各后端，后端不可用（连接失败、5xx、超时）时自动切换到下一个；
+// 每个后端有独立的熔断器，连续失败达到阈值后暂时跳过。设置对冲延迟后，
+// 当前后端在延迟内未返回时会并发请求下一个后端，取最先成功的结果。
+// 全部后端不可用时使用备用解析器（如进程内的原生解析器）。
+type FailoverParser struct {
+	mu         sync.RWMutex
+	platform   Platform
+	primary    Parser // 第一个后端的解析器，创建后不再改变，用于提取ID和校验请求
+	backends   []*failoverBackend
+	fallback   Parser
+	hedgeDelay time.Duration
+}
+
+// NewFailoverParser 创建故障转移解析器，所有解析器必须属于同一平台
+func NewFailoverParser(parsers ...Parser) (*FailoverParser, error) {
+	if len(parsers) == 0 {
+		return nil, fmt.Errorf("at least one parser is required")
+	}
+
+	platform := parsers[0].GetPlatform()
+	backends := make([]*failoverBackend, 0, len(parsers))
+	for i, parser := range parsers {
+		if parser.GetPlatform() != platform {
+			return nil, fmt.Errorf("parser platform mismatch: expected %s, got %s", platform, parser.GetPlatform())
+		}
+
+		name := fmt.Sprintf("%s#%d", platform, i)
+		if backend, ok := parser.(BackendProvider); ok {
+			name = backend.BaseURL()
+		}
+		backends = append(backends, &failoverBackend{
+			name:    name,
+			parser:  parser,
+			breaker: NewCircuitBreaker(5, 30*time.Second),
+		})
+	}
+
+	return &FailoverParser{platform: platform, primary: parsers[0], backends: backends}, nil
+}
+
+// SetCircuitBreaker 设置熔断阈值（连续失败次数）和熔断持续时间，已有的熔断状态会被重置
+func (p *FailoverParser) SetCircuitBreaker(failureThreshold int, openTimeout time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// 替换整个后端列表，避免与进行中的请求竞争
+	backends := make([]*failoverBackend, 0, len(p.backends))
+	for _, b := range p.backends {
+		backends = append(backends, &failoverBackend{
+			name:    b.name,
+			parser:  b.parser,
+			breaker: NewCircuitBreaker(failureThreshold, openTimeout),
+		})
+	}
+	p.backends = backends
+}
+
+// SetHedgeDelay 设置对冲延迟，<=0表示只在失败后切换后端
+func (p *FailoverParser) SetHedgeDelay(delay time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hedgeDelay = delay
+}
+
+// SetFallback 设置全部后端不可用时使用的备用解析器，传nil取消
+func (p *FailoverParser) SetFallback(parser Parser) error {
+	if parser != nil && parser.GetPlatform() != p.platform {
+		return fmt.Errorf("fallback platform mismatch: expected %s, got %s", p.platform, parser.GetPlatform())
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fallback = parser
+	return nil
+}
+
+// GetPlatform 获取平台类型
+func (p *FailoverParser) GetPlatform() Platform {
+	return p.platform
+}
+
+// ExtractVideoID 从URL提取视频ID
+func (p *FailoverParser) ExtractVideoID(url string) (string, error) {
+	return p.primary.ExtractVideoID(url)
+}
+
+// ValidateRequest 验证请求参数
+func (p *FailoverParser) ValidateRequest(req *ParseRequest) error {
+	return p.primary.ValidateRequest(req)
+}
+
+// ParseVideo 解析视频信息，按顺序在可用后端间故障转移
+func (p *FailoverParser) ParseVideo(ctx context.Context, req *ParseRequest) (*VideoInfo, error) {
+	p.mu.RLock()
+	backends := p.backends
+	fallback := p.fallback
+	hedgeDelay := p.hedgeDelay
+	p.mu.RUnlock()
+
+	attemptCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan attemptResult, len(backends))
+	next, inFlight := 0, 0
+	var lastErr error
+	launch := func() bool {
+		for next < len(backends) {
+			b := backends[next]
+			next++
+			if !b.breaker.Allow() {
+				continue
+			}
+			if inFlight > 0 || lastErr != nil {
+				MetricsFromContext(ctx).Retry(p.platform, b.name)
+			}
+			inFlight++
+			go func() {
+				info, err := p.attempt(attemptCtx, b, req)
+				results <- attemptResult{info: info, err: err}
+			}()
+			return true
+		}
+		return false
+	}
+
+	var hedge <-chan time.Time
+	resetHedge := func() {
+		if hedgeDelay > 0 && next < len(backends) {
+			hedge = time.After(hedgeDelay)
+		} else {
+			hedge = nil
+		}
+	}
+
+	if launch() {
+		resetHedge()
+	}
+	for inFlight > 0 {
+		select {
+		case result := <-results:
+			inFlight--
+			if result.err == nil {
+				return result.info, nil
+			}
+			if !p.retryable(ctx, result.err) {
+				return nil, result.err
+			}
+			lastErr = result.err
+			if launch() {
+				resetHedge()
+			}
+		case <-hedge:
+			if launch() {
+				resetHedge()
+			} else {
+				hedge = nil
+			}
+		case <-ctx.Done():
+			return nil, NewError(ErrorCodeOf(ctx.Err()), ctx.Err())
+		}
+	}
+
+	if fallback != nil {
+		return fallback.ParseVideo(ctx, req)
+	}
+	if lastErr == nil {
+		return nil, NewError(ErrCodeBackendUnavailable, fmt.Errorf("all %s backends are unavailable (circuit open)", p.platform))
+	}
+	return nil, NewError(ErrCodeBackendUnavailable, fmt.Errorf("all %s backends failed: %w", p.platform, lastErr))
+}
+
+// attempt 请求单个后端并更新其熔断器
+func (p *FailoverParser) attempt(ctx context.Context, b *failoverBackend, req *ParseRequest) (*VideoInfo, error) {
+	release, err := acquireBackendLimit(ctx, b.name)
+	if err != nil {
+		b.breaker.Release()
+		return nil, err
+	}
+	defer release()
+
+	info, err := b.parser.ParseVideo(ctx, req)
+	switch {
+	case err == nil:
+		b.breaker.Success()
+	case ctx.Err() != nil:
+		// 对冲请求中其他后端已成功或调用方取消，不计入失败
+		b.breaker.Release()
+	case isBackendFailure(err):
+		b.breaker.Failure(err)
+		LoggerFromContext(ctx).WarnContext(ctx, "backend unavailable",
+			slog.String("platform", string(p.platform)),
+			slog.String("backend", b.name),
+			slog.String("circuit", string(b.breaker.State())),
+			slog.Any("error", err),
+		)
+	default:
+		// 后端正常响应但内容解析失败，说明后端本身可用
+		b.breaker.Success()
+	}
+	return info, err
+}
+
+// retryable 错误是否应切换到下一个后端
+func (p *FailoverParser) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return isBackendFailure(err) || ErrorCodeOf(err) == ErrCodeRateLimited
+}
+
+// SetTransport 为全部实现了TransportSetter的后端解析器设置HTTP传输层
+func (p *FailoverParser) SetTransport(transport http.RoundTripper) {
+	p.mu.RLock()
+	backends := p.backends
+	fallback := p.fallback
+	p.mu.RUnlock()
+
+	for _, b := range backends {
+		if setter, ok := b.parser.(TransportSetter); ok {
+			setter.SetTransport(transport)
+		}
+	}
+	if setter, ok := fallback.(TransportSetter); ok {
+		setter.SetTransport(transport)
+	}
+}
+
+// CheckHealth 检查全部后端，失败的后端立即熔断，恢复的后端重新启用
+//
+// 未实现HealthChecker的后端跳过检查。返回全部失败后端的错误。
+func (p *FailoverParser) CheckHealth(ctx context.Context) error {
+	p.mu.RLock()
+	backends := p.backends
+	p.mu.RUnlock()
+
+	var errs []error
+	for _, b := range backends {
+		checker, ok := b.parser.(HealthChecker)
+		if !ok {
+			continue
+		}
+		if err := checker.HealthCheck(ctx); err != nil {
+			b.breaker.Trip(err)
+			errs = append(errs, fmt.Errorf("%s: %w", b.name, err))
+			continue
+		}
+		if b.breaker.State() != CircuitClosed {
+			b.breaker.Success()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Start 按固定间隔执行健康检查，直到ctx被取消
+func (p *FailoverParser) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	_ = p.CheckHealth(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = p.CheckHealth(ctx)
+		}
+	}
+}
+
+// Status 获取全部后端的状态
+func (p *FailoverParser) Status() []BackendStatus {
+	p.mu.RLock()
+	backends := p.backends
+	p.mu.RUnlock()
+
+	statuses := make([]BackendStatus, 0, len(backends))
+	for _, b := range backends {
+		failures, lastError := b.breaker.failures()
+		statuses = append(statuses, BackendStatus{
+			Backend:             b.name,
+			State:               b.breaker.State(),
+			ConsecutiveFailures: failures,
+			LastError:           lastError,
+		})
+	}
+	return statuses
+}
+
+// isBackendFailure 错误是否表示后端服务本身不可用
+func isBackendFailure(err error) bool {
+	switch ErrorCodeOf(err) {
+	case ErrCodeBackendUnavailable, ErrCodeTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package videosdk
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubParser 返回固定结果的解析器
+type stubParser struct {
+	platform Platform
+}
+
+func (p stubParser) GetPlatform() Platform                     { return p.platform }
+func (p stubParser) ExtractVideoID(url string) (string, error) { return url, nil }
+func (p stubParser) ValidateRequest(req *ParseRequest) error   { return nil }
+
+func (p stubParser) ParseVideo(ctx context.Context, req *ParseRequest) (*VideoInfo, error) {
+	return &VideoInfo{ID: req.VideoID}, nil
+}
+
+func TestFailoverParserConcurrentConfig(t *testing.T) {
+	parser, err := NewFailoverParser(stubParser{PlatformDouyin}, stubParser{PlatformDouyin})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &ParseRequest{Platform: PlatformDouyin, VideoID: "1"}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := parser.ValidateRequest(req); err != nil {
+				t.Error(err)
+			}
+			if _, err := parser.ExtractVideoID("https://www.douyin.com/video/1"); err != nil {
+				t.Error(err)
+			}
+			if _, err := parser.ParseVideo(context.Background(), req); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			parser.SetCircuitBreaker(3, time.Second)
+		}()
+	}
+	wg.Wait()
+}
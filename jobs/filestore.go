@@ -0,0 +1,224 @@
+package jobs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// fileRecord 任务日志中的一条记录
+type fileRecord struct {
+	Op  string          `json:"op"`            // put或delete
+	ID  string          `json:"id"`            // 任务ID
+	Job json.RawMessage `json:"job,omitempty"` // put时的任务数据
+}
+
+// FileStore 单文件任务存储
+//
+// 任务以追加日志的形式写入文件，每次写入后同步到磁盘；打开时重放日志恢复任务，
+// 末尾写了一半的记录（进程在写入时崩溃）会被忽略。日志中的过期记录超过一定数量时自动压缩。
+type FileStore struct {
+	mu      sync.RWMutex
+	path    string
+	file    *os.File
+	jobs    map[string][]byte
+	records int
+}
+
+// OpenFileStore 打开或创建单文件任务存储
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, jobs: make(map[string][]byte)}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open job store: %w", err)
+	}
+	s.file = file
+
+	// 清除末尾不完整的记录
+	if err := s.compact(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// load 重放任务日志
+func (s *FileStore) load() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read job store: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 64<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var record fileRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			// 只有最后一条记录可能写了一半
+			continue
+		}
+		switch record.Op {
+		case "put":
+			s.jobs[record.ID] = []byte(record.Job)
+		case "delete":
+			delete(s.jobs, record.ID)
+		}
+		s.records++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read job store: %w", err)
+	}
+	return nil
+}
+
+// Save 新增或覆盖任务
+func (s *FileStore) Save(ctx context.Context, job *Job) error {
+	data, err := encodeJob(job)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.append(fileRecord{Op: "put", ID: job.ID, Job: data}); err != nil {
+		return err
+	}
+	s.jobs[job.ID] = data
+	return s.maybeCompact()
+}
+
+// Get 获取任务
+func (s *FileStore) Get(ctx context.Context, id string) (*Job, error) {
+	s.mu.RLock()
+	data, ok := s.jobs[id]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return decodeJob(data)
+}
+
+// List 按提交时间倒序列出任务
+func (s *FileStore) List(ctx context.Context, filter Filter) ([]*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return listJobs(s.jobs, filter)
+}
+
+// Delete 删除任务
+func (s *FileStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[id]; !ok {
+		return ErrNotFound
+	}
+	if err := s.append(fileRecord{Op: "delete", ID: id}); err != nil {
+		return err
+	}
+	delete(s.jobs, id)
+	return s.maybeCompact()
+}
+
+// Close 关闭存储
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// append 追加一条记录并同步到磁盘（调用方需持有锁）
+func (s *FileStore) append(record fileRecord) error {
+	if s.file == nil {
+		return fmt.Errorf("job store is closed")
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encode job record: %w", err)
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write job store: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("sync job store: %w", err)
+	}
+	s.records++
+	return nil
+}
+
+// maybeCompact 过期记录较多时压缩日志（调用方需持有锁）
+func (s *FileStore) maybeCompact() error {
+	if s.records <= 2*len(s.jobs)+100 {
+		return nil
+	}
+	return s.compact()
+}
+
+// compact 将当前任务写入新文件并替换日志（调用方需持有锁）
+func (s *FileStore) compact() error {
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("compact job store: %w", err)
+	}
+
+	w := bufio.NewWriter(tmp)
+	for id, data := range s.jobs {
+		line, err := json.Marshal(fileRecord{Op: "put", ID: id, Job: data})
+		if err == nil {
+			_, err = w.Write(append(line, '\n'))
+		}
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+			return fmt.Errorf("compact job store: %w", err)
+		}
+	}
+	err = w.Flush()
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("compact job store: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("compact job store: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("reopen job store: %w", err)
+	}
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = file
+	s.records = len(s.jobs)
+	return nil
+}
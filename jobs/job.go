@@ -0,0 +1,125 @@
+// Package jobs 提供异步的解析和下载任务
+//
+// Manager接收解析或下载任务后立即返回任务ID，由后台工作协程逐个执行任务中的请求，
+// 调用方可以轮询任务状态和进度、取消任务，或在任务结束时通过Webhook接收带HMAC签名的回调。
+// 任务保存在可替换的Store中（内存、单文件日志或SQLite），服务重启后未完成的任务会继续执行：
+//
+//	store, _ := jobs.OpenSQLiteStore("jobs.db")
+//	manager := jobs.NewManager(sdk, store, jobs.Config{WebhookSecret: "secret"})
+//	_ = manager.Start(ctx)
+//	defer manager.Close()
+//
+//	job, _ := manager.Submit(ctx, &jobs.Request{
+//		Type:     jobs.TypeDownload,
+//		Requests: reqs,
+//		Webhook:  "https://example.com/hooks/videosdk",
+//	})
+package jobs
+
+import (
+	"errors"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// Type 任务类型
+type Type string
+
+const (
+	TypeParse    Type = "parse"    // 解析任务
+	TypeDownload Type = "download" // 解析并下载全部媒体文件
+)
+
+// Status 任务状态
+type Status string
+
+const (
+	StatusQueued    Status = "queued"    // 等待执行
+	StatusRunning   Status = "running"   // 执行中
+	StatusCompleted Status = "completed" // 已完成，部分请求可能失败（见Progress.Failed）
+	StatusFailed    Status = "failed"    // 全部请求失败
+	StatusCanceled  Status = "canceled"  // 已取消
+)
+
+// Finished 任务是否已结束
+func (s Status) Finished() bool {
+	switch s {
+	case StatusCompleted, StatusFailed, StatusCanceled:
+		return true
+	}
+	return false
+}
+
+var (
+	// ErrNotFound 任务不存在
+	ErrNotFound = errors.New("job not found")
+	// ErrFinished 任务已结束，无法取消
+	ErrFinished = errors.New("job already finished")
+	// ErrNotFinished 任务未结束，无法删除
+	ErrNotFinished = errors.New("job not finished")
+)
+
+// Request 提交任务的请求
+type Request struct {
+	Type     Type                     `json:"type"`              // 任务类型，默认TypeParse
+	Requests []*videosdk.ParseRequest `json:"requests"`          // 解析请求列表
+	Webhook  string                   `json:"webhook,omitempty"` // 任务结束时回调的地址（可选）
+}
+
+// Job 异步任务
+//
+// Requests中的Cookie和代理地址在提交时被移除，只在Credentials中保留脱敏标识，
+// 因此存储、查询接口和Webhook都不会包含Cookie或代理密码。
+type Job struct {
+	ID          string                   `json:"id"`                    // 任务ID
+	Type        Type                     `json:"type"`                  // 任务类型
+	Status      Status                   `json:"status"`                // 任务状态
+	Requests    []*videosdk.ParseRequest `json:"requests"`              // 解析请求列表（不含Cookie和代理）
+	Credentials []*Credential            `json:"credentials,omitempty"` // 与请求一一对应的Cookie和代理标识，未提供时为null
+	Webhook     string                   `json:"webhook,omitempty"`     // 回调地址
+	Progress    Progress                 `json:"progress"`              // 执行进度
+	Results     []*Result                `json:"results"`               // 与请求一一对应的结果，未执行的请求为null
+	Error       string                   `json:"error,omitempty"`       // 任务级别的错误
+
+	CreatedAt  time.Time `json:"created_at"`  // 提交时间
+	UpdatedAt  time.Time `json:"updated_at"`  // 最后更新时间
+	StartedAt  time.Time `json:"started_at"`  // 开始执行的时间，未开始时为零值
+	FinishedAt time.Time `json:"finished_at"` // 结束时间，未结束时为零值
+
+	Delivery *Delivery `json:"delivery,omitempty"` // Webhook投递状态
+}
+
+// Credential 请求附带的Cookie和代理的脱敏标识
+type Credential struct {
+	CookieID string `json:"cookie_id,omitempty"` // Cookie的标识（videosdk.CookieID）
+	Proxy    string `json:"proxy,omitempty"`     // 隐去密码的代理地址
+}
+
+// Progress 任务进度
+type Progress struct {
+	Total     int `json:"total"`     // 请求总数
+	Completed int `json:"completed"` // 已执行的请求数（含失败）
+	Failed    int `json:"failed"`    // 失败的请求数
+}
+
+// Result 单个请求的执行结果
+type Result struct {
+	Response      *videosdk.ParseResponse `json:"response"`                 // 解析结果
+	Files         []string                `json:"files,omitempty"`          // 下载任务保存的文件路径
+	DownloadError string                  `json:"download_error,omitempty"` // 下载失败的原因
+}
+
+// Failed 请求是否失败
+func (r *Result) Failed() bool {
+	return r.Response == nil || !r.Response.Success || r.DownloadError != ""
+}
+
+// Delivery Webhook投递状态
+type Delivery struct {
+	Attempts    int       `json:"attempts"`             // 已尝试次数
+	Delivered   bool      `json:"delivered"`            // 是否投递成功
+	LastStatus  int       `json:"last_status"`          // 最后一次响应的HTTP状态码
+	LastError   string    `json:"last_error,omitempty"` // 最后一次失败的原因
+	DeliveredAt time.Time `json:"delivered_at"`         // 投递成功的时间
+}
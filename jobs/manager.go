@@ -0,0 +1,567 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// Config 任务管理器配置
+type Config struct {
+	Workers        int                  // 同时执行的任务数，默认2
+	Concurrency    int                  // 单个任务内同时执行的请求数，默认4
+	MaxRequests    int                  // 单个任务的最大请求数，默认1000
+	Downloader     *videosdk.Downloader // 下载任务使用的下载器，默认使用videosdk.NewDownloader()
+	DownloadDir    string               // 下载任务的保存目录，每个任务使用以任务ID命名的子目录，默认"downloads"
+	WebhookSecret  string               // Webhook签名密钥，为空时不签名
+	WebhookRetries int                  // Webhook投递失败后的重试次数，默认3，<0时不重试
+	WebhookBackoff time.Duration        // 首次重试前的等待时间，之后每次翻倍，默认1秒
+	WebhookTimeout time.Duration        // 单次投递的超时时间，默认10秒
+	HTTPClient     *http.Client         // 投递Webhook使用的HTTP客户端，默认使用http.DefaultClient
+	Logger         *slog.Logger         // 日志，默认使用slog.Default()
+}
+
+// Manager 异步任务管理器
+type Manager struct {
+	sdk    videosdk.SDK
+	store  Store
+	config Config
+	logger *slog.Logger
+
+	mu       sync.Mutex // 串行化任务状态的读-改-写
+	running  map[string]context.CancelFunc
+	secrets  map[string][]*videosdk.ParseRequest // 未结束任务的原始请求（含Cookie和代理），只保存在内存中
+	incoming chan string
+	work     chan string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewManager 创建任务管理器，调用Start后开始执行任务
+func NewManager(sdk videosdk.SDK, store Store, config Config) *Manager {
+	if config.Workers <= 0 {
+		config.Workers = 2
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 4
+	}
+	if config.MaxRequests <= 0 {
+		config.MaxRequests = 1000
+	}
+	if config.Downloader == nil {
+		config.Downloader = videosdk.NewDownloader()
+	}
+	if config.DownloadDir == "" {
+		config.DownloadDir = "downloads"
+	}
+	if config.WebhookRetries < 0 {
+		config.WebhookRetries = 0
+	} else if config.WebhookRetries == 0 {
+		config.WebhookRetries = 3
+	}
+	if config.WebhookBackoff <= 0 {
+		config.WebhookBackoff = time.Second
+	}
+	if config.WebhookTimeout <= 0 {
+		config.WebhookTimeout = 10 * time.Second
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Manager{
+		sdk:      sdk,
+		store:    store,
+		config:   config,
+		logger:   logger,
+		running:  make(map[string]context.CancelFunc),
+		secrets:  make(map[string][]*videosdk.ParseRequest),
+		incoming: make(chan string),
+		work:     make(chan string),
+	}
+}
+
+// Start 启动工作协程，并恢复存储中未完成的任务和未投递的Webhook
+//
+// 上次运行时执行到一半的任务会从未完成的请求继续执行。请求中的Cookie和代理不会被持久化，
+// 恢复的任务不再带有提交时的Cookie和代理，由SDK的CookieJar、Cookie池和代理池提供。
+func (m *Manager) Start(ctx context.Context) error {
+	pending, err := m.store.List(ctx, Filter{Status: []Status{StatusQueued, StatusRunning}})
+	if err != nil {
+		return fmt.Errorf("load pending jobs: %w", err)
+	}
+	finished, err := m.store.List(ctx, Filter{Status: []Status{StatusCompleted, StatusFailed, StatusCanceled}})
+	if err != nil {
+		return fmt.Errorf("load finished jobs: %w", err)
+	}
+
+	m.ctx, m.cancel = context.WithCancel(context.WithoutCancel(ctx))
+
+	m.wg.Add(1)
+	go m.dispatch()
+	for i := 0; i < m.config.Workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+
+	// List按提交时间倒序返回，先提交的任务先执行
+	for i := len(pending) - 1; i >= 0; i-- {
+		m.enqueue(pending[i].ID)
+	}
+	for _, job := range finished {
+		if job.Webhook != "" && (job.Delivery == nil || (!job.Delivery.Delivered &&
+			job.Delivery.Attempts <= m.config.WebhookRetries && retryable(job.Delivery.LastStatus))) {
+			m.goNotify(job)
+		}
+	}
+	return nil
+}
+
+// Close 停止执行任务并等待工作协程退出，不关闭存储
+//
+// 执行中的任务保持未完成状态，下次Start时继续执行；未投递的Webhook同样在下次Start时重试。
+func (m *Manager) Close() error {
+	if m.cancel == nil {
+		return nil
+	}
+	m.cancel()
+	m.wg.Wait()
+	return nil
+}
+
+// Submit 提交任务，返回排队中的任务
+func (m *Manager) Submit(ctx context.Context, req *Request) (*Job, error) {
+	if m.ctx == nil {
+		return nil, fmt.Errorf("job manager is not started")
+	}
+	if err := m.validate(req); err != nil {
+		return nil, videosdk.NewError(videosdk.ErrCodeInvalidRequest, err)
+	}
+
+	jobType := req.Type
+	if jobType == "" {
+		jobType = TypeParse
+	}
+	now := time.Now()
+	requests, credentials := redactRequests(req.Requests)
+	job := &Job{
+		ID:          newJobID(),
+		Type:        jobType,
+		Status:      StatusQueued,
+		Requests:    requests,
+		Credentials: credentials,
+		Webhook:     req.Webhook,
+		Progress:    Progress{Total: len(req.Requests)},
+		Results:     make([]*Result, len(req.Requests)),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if credentials != nil {
+		originals := make([]*videosdk.ParseRequest, len(req.Requests))
+		for i, r := range req.Requests {
+			original := *r
+			originals[i] = &original
+		}
+		m.mu.Lock()
+		m.secrets[job.ID] = originals
+		m.mu.Unlock()
+	}
+	if err := m.store.Save(ctx, job); err != nil {
+		m.mu.Lock()
+		delete(m.secrets, job.ID)
+		m.mu.Unlock()
+		return nil, err
+	}
+
+	m.enqueue(job.ID)
+	return job, nil
+}
+
+// redactRequests 复制请求并移除Cookie和代理，返回与请求一一对应的脱敏标识，没有任何请求带Cookie或代理时返回nil
+func redactRequests(reqs []*videosdk.ParseRequest) ([]*videosdk.ParseRequest, []*Credential) {
+	redacted := make([]*videosdk.ParseRequest, len(reqs))
+	var credentials []*Credential
+	for i, r := range reqs {
+		copied := *r
+		copied.Cookie = ""
+		copied.Proxy = ""
+		redacted[i] = &copied
+
+		if r.Cookie == "" && r.Proxy == "" {
+			continue
+		}
+		if credentials == nil {
+			credentials = make([]*Credential, len(reqs))
+		}
+		credentials[i] = &Credential{CookieID: videosdk.CookieID(r.Cookie)}
+		if r.Proxy != "" {
+			credentials[i].Proxy = videosdk.RedactURL(r.Proxy)
+		}
+	}
+	return redacted, credentials
+}
+
+// validate 校验提交的任务
+func (m *Manager) validate(req *Request) error {
+	if req == nil {
+		return fmt.Errorf("request cannot be nil")
+	}
+	switch req.Type {
+	case "", TypeParse, TypeDownload:
+	default:
+		return fmt.Errorf("unknown job type %q", req.Type)
+	}
+	if len(req.Requests) == 0 {
+		return fmt.Errorf("requests cannot be empty")
+	}
+	if len(req.Requests) > m.config.MaxRequests {
+		return fmt.Errorf("too many requests, max is %d", m.config.MaxRequests)
+	}
+	for i, r := range req.Requests {
+		if r == nil {
+			return fmt.Errorf("request %d cannot be nil", i)
+		}
+	}
+	if req.Webhook != "" {
+		u, err := url.Parse(req.Webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook url: %q", req.Webhook)
+		}
+	}
+	return nil
+}
+
+// Get 获取任务
+func (m *Manager) Get(ctx context.Context, id string) (*Job, error) {
+	return m.store.Get(ctx, id)
+}
+
+// List 按提交时间倒序列出任务
+func (m *Manager) List(ctx context.Context, filter Filter) ([]*Job, error) {
+	return m.store.List(ctx, filter)
+}
+
+// Cancel 取消任务，已结束的任务返回ErrFinished
+//
+// 排队中的任务立即取消；执行中的任务停止发起新的请求，已完成的结果会保留。
+func (m *Manager) Cancel(ctx context.Context, id string) (*Job, error) {
+	m.mu.Lock()
+	job, err := m.store.Get(ctx, id)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	if job.Status.Finished() {
+		m.mu.Unlock()
+		return job, ErrFinished
+	}
+
+	if cancel, ok := m.running[id]; ok {
+		// 由执行任务的协程保存取消状态
+		cancel()
+		m.mu.Unlock()
+		return job, nil
+	}
+
+	m.finish(job, StatusCanceled)
+	err = m.store.Save(ctx, job)
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	m.goNotify(job)
+	return job, nil
+}
+
+// Delete 删除已结束的任务，未结束的任务返回ErrNotFinished
+func (m *Manager) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !job.Status.Finished() {
+		return ErrNotFinished
+	}
+	delete(m.secrets, id)
+	return m.store.Delete(ctx, id)
+}
+
+// update 读取任务、修改并保存
+func (m *Manager) update(ctx context.Context, id string, fn func(job *Job) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := fn(job); err != nil {
+		return err
+	}
+	job.UpdatedAt = time.Now()
+	return m.store.Save(ctx, job)
+}
+
+// enqueue 将任务加入执行队列
+func (m *Manager) enqueue(id string) {
+	select {
+	case m.incoming <- id:
+	case <-m.ctx.Done():
+	}
+}
+
+// dispatch 维护不限长度的执行队列，按提交顺序分发给工作协程
+func (m *Manager) dispatch() {
+	defer m.wg.Done()
+
+	var queue []string
+	for {
+		var work chan string
+		var next string
+		if len(queue) > 0 {
+			work = m.work
+			next = queue[0]
+		}
+
+		select {
+		case id := <-m.incoming:
+			queue = append(queue, id)
+		case work <- next:
+			queue = queue[1:]
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// worker 工作协程
+func (m *Manager) worker() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case id := <-m.work:
+			m.run(id)
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// run 执行任务
+func (m *Manager) run(id string) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	defer cancel()
+
+	// 取出任务并标记为执行中；任务在排队期间被取消或删除时跳过
+	m.mu.Lock()
+	job, err := m.store.Get(ctx, id)
+	if err != nil || job.Status.Finished() {
+		m.mu.Unlock()
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			m.logger.Error("load job failed", "job", id, "error", err)
+		}
+		return
+	}
+	now := time.Now()
+	job.Status = StatusRunning
+	if job.StartedAt.IsZero() {
+		job.StartedAt = now
+	}
+	job.UpdatedAt = now
+	if len(job.Results) != len(job.Requests) {
+		job.Results = make([]*Result, len(job.Requests))
+	}
+	err = m.store.Save(ctx, job)
+	if err == nil {
+		m.running[id] = cancel
+	}
+	originals := m.secrets[id]
+	m.mu.Unlock()
+	if err != nil {
+		m.logger.Error("save job failed", "job", id, "error", err)
+		return
+	}
+	defer func() {
+		m.mu.Lock()
+		delete(m.running, id)
+		m.mu.Unlock()
+	}()
+
+	m.logger.Info("job started", "job", id, "type", job.Type, "requests", len(job.Requests))
+	if originals == nil && job.Credentials != nil {
+		m.logger.Warn("job cookies and proxies were not persisted, resuming without them", "job", id)
+	}
+	m.execute(ctx, job, originals)
+
+	// 服务关闭时保持执行中状态，下次启动时继续执行
+	if m.ctx.Err() != nil {
+		return
+	}
+
+	status := StatusCompleted
+	switch {
+	case ctx.Err() != nil:
+		status = StatusCanceled
+	case job.Progress.Failed == job.Progress.Total:
+		status = StatusFailed
+	}
+
+	m.mu.Lock()
+	m.finish(job, status)
+	err = m.store.Save(context.WithoutCancel(ctx), job)
+	m.mu.Unlock()
+	if err != nil {
+		m.logger.Error("save job failed", "job", id, "error", err)
+		return
+	}
+
+	m.logger.Info("job finished", "job", id, "status", status,
+		"completed", job.Progress.Completed, "failed", job.Progress.Failed)
+	m.goNotify(job)
+}
+
+// execute 并发执行任务中未完成的请求，每完成一个请求保存一次进度
+//
+// originals为提交时的原始请求，为nil时（服务重启后）使用不含Cookie和代理的请求。
+func (m *Manager) execute(ctx context.Context, job *Job, originals []*videosdk.ParseRequest) {
+	sem := make(chan struct{}, m.config.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, req := range job.Requests {
+		if job.Results[i] != nil {
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if originals != nil {
+			req = originals[i]
+		}
+
+		wg.Add(1)
+		go func(i int, req *videosdk.ParseRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := m.executeRequest(ctx, job, req)
+			if ctx.Err() != nil {
+				// 被取消或服务关闭的请求不记录结果，恢复后重新执行
+				return
+			}
+
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			job.Results[i] = result
+			job.Progress = progressOf(job.Results)
+			job.UpdatedAt = time.Now()
+			if err := m.store.Save(context.WithoutCancel(ctx), job); err != nil {
+				m.logger.Error("save job progress failed", "job", job.ID, "error", err)
+			}
+		}(i, req)
+	}
+
+	wg.Wait()
+}
+
+// executeRequest 执行单个请求，下载任务在解析成功后下载全部媒体文件
+func (m *Manager) executeRequest(ctx context.Context, job *Job, req *videosdk.ParseRequest) *Result {
+	resp, err := m.sdk.ParseVideo(ctx, req)
+	if resp == nil {
+		resp = &videosdk.ParseResponse{Code: videosdk.ErrorCodeOf(err), Time: time.Now()}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+	}
+	// 错误信息可能包含代理地址等凭据
+	resp.Error = videosdk.RedactText(resp.Error)
+	result := &Result{Response: resp}
+	if job.Type != TypeDownload || !resp.Success {
+		return result
+	}
+
+	files, err := m.config.Downloader.Download(ctx, resp.Data, filepath.Join(m.config.DownloadDir, job.ID))
+	result.Files = files
+	if err != nil {
+		result.DownloadError = err.Error()
+	}
+	return result
+}
+
+// progressOf 根据结果统计进度
+func progressOf(results []*Result) Progress {
+	progress := Progress{Total: len(results)}
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		progress.Completed++
+		if result.Failed() {
+			progress.Failed++
+		}
+	}
+	return progress
+}
+
+// finish 将任务标记为结束（调用方需持有m.mu）
+func (m *Manager) finish(job *Job, status Status) {
+	delete(m.secrets, job.ID)
+
+	now := time.Now()
+	job.Status = status
+	job.Progress = progressOf(job.Results)
+	job.FinishedAt = now
+	job.UpdatedAt = now
+}
+
+// goNotify 在后台投递Webhook，投递使用任务的副本
+func (m *Manager) goNotify(job *Job) {
+	if job.Webhook == "" || m.ctx == nil {
+		return
+	}
+
+	snapshot := *job
+	if job.Delivery != nil {
+		delivery := *job.Delivery
+		snapshot.Delivery = &delivery
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.notify(m.ctx, &snapshot)
+	}()
+}
+
+// newJobID 生成随机的任务ID
+func newJobID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("jobs: generate id: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
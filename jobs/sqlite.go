@@ -0,0 +1,132 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	_ "modernc.org/sqlite" // 纯Go实现的SQLite驱动
+)
+
+// SQLiteStore SQLite任务存储，任务以JSON保存在jobs表中
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// OpenSQLiteStore 打开或创建SQLite任务存储
+func OpenSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("open job store: %w", err)
+	}
+	// SQLite同一时间只允许一个写入者，使用单连接避免锁冲突
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS jobs (
+			id         TEXT PRIMARY KEY,
+			status     TEXT NOT NULL,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL,
+			data       TEXT NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS jobs_status ON jobs (status, created_at);
+	`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init job store: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// Save 新增或覆盖任务
+func (s *SQLiteStore) Save(ctx context.Context, job *Job) error {
+	data, err := encodeJob(job)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO jobs (id, status, created_at, updated_at, data) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET status = excluded.status, updated_at = excluded.updated_at, data = excluded.data`,
+		job.ID, string(job.Status), job.CreatedAt.UnixNano(), job.UpdatedAt.UnixNano(), string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("save job: %w", err)
+	}
+	return nil
+}
+
+// Get 获取任务
+func (s *SQLiteStore) Get(ctx context.Context, id string) (*Job, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM jobs WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+	return decodeJob([]byte(data))
+}
+
+// List 按提交时间倒序列出任务
+func (s *SQLiteStore) List(ctx context.Context, filter Filter) ([]*Job, error) {
+	query := `SELECT data FROM jobs`
+	var args []interface{}
+	if len(filter.Status) > 0 {
+		placeholders := make([]string, len(filter.Status))
+		for i, status := range filter.Status {
+			placeholders[i] = "?"
+			args = append(args, string(status))
+		}
+		query += ` WHERE status IN (` + strings.Join(placeholders, ", ") + `)`
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("list jobs: %w", err)
+		}
+		job, err := decodeJob([]byte(data))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Delete 删除任务
+func (s *SQLiteStore) Delete(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM jobs WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete job: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Close 关闭存储
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,145 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Store 任务存储
+//
+// Get和List返回的任务是副本，修改后需要调用Save保存。
+type Store interface {
+	// Save 新增或覆盖任务
+	Save(ctx context.Context, job *Job) error
+	// Get 获取任务，不存在时返回ErrNotFound
+	Get(ctx context.Context, id string) (*Job, error)
+	// List 按提交时间倒序列出任务
+	List(ctx context.Context, filter Filter) ([]*Job, error)
+	// Delete 删除任务，不存在时返回ErrNotFound
+	Delete(ctx context.Context, id string) error
+	// Close 关闭存储
+	Close() error
+}
+
+// Filter 列出任务的条件
+type Filter struct {
+	Status []Status // 只列出这些状态的任务，为空时不限制
+	Limit  int      // 最多返回的数量，<=0时不限制
+}
+
+// match 任务是否符合条件
+func (f Filter) match(job *Job) bool {
+	if len(f.Status) == 0 {
+		return true
+	}
+	for _, status := range f.Status {
+		if job.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// MemoryStore 内存任务存储，进程退出后任务丢失
+type MemoryStore struct {
+	mu   sync.RWMutex
+	jobs map[string][]byte
+}
+
+// NewMemoryStore 创建内存任务存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: make(map[string][]byte)}
+}
+
+// Save 新增或覆盖任务
+func (s *MemoryStore) Save(ctx context.Context, job *Job) error {
+	data, err := encodeJob(job)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = data
+	return nil
+}
+
+// Get 获取任务
+func (s *MemoryStore) Get(ctx context.Context, id string) (*Job, error) {
+	s.mu.RLock()
+	data, ok := s.jobs[id]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return decodeJob(data)
+}
+
+// List 按提交时间倒序列出任务
+func (s *MemoryStore) List(ctx context.Context, filter Filter) ([]*Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return listJobs(s.jobs, filter)
+}
+
+// Delete 删除任务
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.jobs, id)
+	return nil
+}
+
+// Close 关闭存储
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// encodeJob 编码任务
+func encodeJob(job *Job) ([]byte, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("encode job: %w", err)
+	}
+	return data, nil
+}
+
+// decodeJob 解码任务
+func decodeJob(data []byte) (*Job, error) {
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("decode job: %w", err)
+	}
+	return &job, nil
+}
+
+// listJobs 解码符合条件的任务，按提交时间倒序排列
+func listJobs(encoded map[string][]byte, filter Filter) ([]*Job, error) {
+	jobs := make([]*Job, 0, len(encoded))
+	for _, data := range encoded {
+		job, err := decodeJob(data)
+		if err != nil {
+			return nil, err
+		}
+		if filter.match(job) {
+			jobs = append(jobs, job)
+		}
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+		}
+		return jobs[i].ID > jobs[j].ID
+	})
+	if filter.Limit > 0 && len(jobs) > filter.Limit {
+		jobs = jobs[:filter.Limit]
+	}
+	return jobs, nil
+}
@@ -0,0 +1,172 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Webhook请求头
+const (
+	HeaderEvent     = "X-Videosdk-Event"     // 事件类型，如job.completed
+	HeaderJobID     = "X-Videosdk-Job"       // 任务ID
+	HeaderTimestamp = "X-Videosdk-Timestamp" // 发送时间（Unix秒）
+	HeaderSignature = "X-Videosdk-Signature" // 签名，格式为"sha256=<hex>"
+)
+
+// Event Webhook回调的请求体
+type Event struct {
+	Event string `json:"event"` // 事件类型：job.completed、job.failed、job.canceled
+	Job   *Job   `json:"job"`   // 结束时的任务
+}
+
+// Sign 计算Webhook签名：HMAC-SHA256(secret, timestamp + "." + body)，返回"sha256=<hex>"
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature 校验Webhook请求的签名，tolerance>0时拒绝发送时间偏差超过tolerance的请求
+//
+// 接收方可以直接使用：
+//
+//	body, _ := io.ReadAll(r.Body)
+//	if err := jobs.VerifySignature(secret, r.Header, body, 5*time.Minute); err != nil {
+//		http.Error(w, err.Error(), http.StatusUnauthorized)
+//	}
+func VerifySignature(secret string, header http.Header, body []byte, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid webhook timestamp: %q", header.Get(HeaderTimestamp))
+	}
+	if tolerance > 0 {
+		if skew := time.Since(time.Unix(timestamp, 0)); skew > tolerance || skew < -tolerance {
+			return fmt.Errorf("webhook timestamp out of tolerance: %s", skew)
+		}
+	}
+
+	expected := Sign(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get(HeaderSignature))) {
+		return fmt.Errorf("invalid webhook signature")
+	}
+	return nil
+}
+
+// eventName 任务结束状态对应的事件类型
+func eventName(status Status) string {
+	return "job." + string(status)
+}
+
+// deliver 投递一次Webhook，返回响应状态码
+func (m *Manager) deliver(ctx context.Context, job *Job) (int, error) {
+	body, err := json.Marshal(&Event{Event: eventName(job.Status), Job: job})
+	if err != nil {
+		return 0, fmt.Errorf("encode webhook event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.config.WebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("create webhook request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "videosdk-webhook")
+	req.Header.Set(HeaderEvent, eventName(job.Status))
+	req.Header.Set(HeaderJobID, job.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	if m.config.WebhookSecret != "" {
+		req.Header.Set(HeaderSignature, Sign(m.config.WebhookSecret, timestamp, body))
+	}
+
+	resp, err := m.config.HTTPClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryable 投递失败后是否需要重试，除408和429外的4xx视为接收方拒绝，不再重试
+func retryable(status int) bool {
+	if status >= 400 && status < 500 {
+		return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// notify 投递任务结束的Webhook，失败时按指数退避重试，投递状态保存到任务中
+func (m *Manager) notify(ctx context.Context, job *Job) {
+	if job.Webhook == "" {
+		return
+	}
+	if job.Delivery == nil {
+		job.Delivery = &Delivery{}
+	}
+
+	backoff := m.config.WebhookBackoff
+	for job.Delivery.Attempts <= m.config.WebhookRetries && !job.Delivery.Delivered {
+		if job.Delivery.Attempts > 0 {
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-ctx.Done():
+				// 服务关闭，重启后继续投递
+				return
+			}
+		}
+
+		status, err := m.deliver(ctx, job)
+		if err != nil && ctx.Err() != nil {
+			return
+		}
+		job.Delivery.Attempts++
+		job.Delivery.LastStatus = status
+		if err == nil {
+			job.Delivery.Delivered = true
+			job.Delivery.DeliveredAt = time.Now()
+			job.Delivery.LastError = ""
+		} else {
+			job.Delivery.LastError = err.Error()
+			m.logger.Warn("webhook delivery failed",
+				"job", job.ID,
+				"attempt", job.Delivery.Attempts,
+				"error", err,
+			)
+		}
+		delivery := *job.Delivery
+		saveErr := m.update(context.WithoutCancel(ctx), job.ID, func(stored *Job) error {
+			stored.Delivery = &delivery
+			return nil
+		})
+		if errors.Is(saveErr, ErrNotFound) {
+			// 任务已被删除
+			return
+		}
+		if saveErr != nil {
+			m.logger.Error("save job failed", "job", job.ID, "error", saveErr)
+		}
+		if err != nil && !retryable(status) {
+			return
+		}
+	}
+}
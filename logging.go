@@ -0,0 +1,211 @@
+package videosdk
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// redacted 脱敏后的占位值
+const redacted = "[REDACTED]"
+
+// sensitiveKeys 属性名或查询参数名中包含这些词时整体脱敏（不区分大小写）
+var sensitiveKeys = []string{"cookie", "token", "password", "passwd", "secret", "authorization", "api_key", "apikey", "x-api-key", "session", "sign"}
+
+// sensitiveTextPattern 匹配文本中形如 "cookie": "..."、token=... 的敏感字段
+var sensitiveTextPattern = regexp.MustCompile(`(?i)("?(?:cookie|[a-z_]*token|password|passwd|secret|authorization|session[a-z_]*)"?\s*[:=]\s*)("(?:[^"\\]|\\.)*"|[^\s&,;}"]+)`)
+
+// urlPattern 匹配文本中的URL
+var urlPattern = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]+`)
+
+// IsSensitiveKey 属性名、字段名或查询参数名是否属于敏感信息（包含cookie、token、password等）
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range sensitiveKeys {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactURL 隐去URL中的密码和敏感查询参数，无法解析时原样返回
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		changed := false
+		for key := range query {
+			if IsSensitiveKey(key) {
+				query.Set(key, redacted)
+				changed = true
+			}
+		}
+		if changed {
+			u.RawQuery = query.Encode()
+		}
+	}
+	return u.String()
+}
+
+// RedactText 隐去文本中的Cookie、Token、密码等字段值以及URL中的凭据，用于记录后端响应片段
+func RedactText(text string) string {
+	text = urlPattern.ReplaceAllStringFunc(text, RedactURL)
+	return sensitiveTextPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := sensitiveTextPattern.FindStringSubmatch(match)
+		if strings.HasPrefix(parts[2], `"`) {
+			return parts[1] + `"` + redacted + `"`
+		}
+		return parts[1] + redacted
+	})
+}
+
+// Excerpt 截取文本开头最多limit个字节（不截断UTF-8字符）并脱敏，用于记录后端响应
+func Excerpt(data []byte, limit int) string {
+	if len(data) > limit {
+		data = data[:limit]
+		for len(data) > 0 && !utf8.Valid(data) {
+			data = data[:len(data)-1]
+		}
+		return RedactText(string(data)) + "..."
+	}
+	return RedactText(string(data))
+}
+
+// LogValue 实现slog.LogValuer，日志中的Cookie只记录标识，代理隐去密码
+func (r *ParseRequest) LogValue() slog.Value {
+	if r == nil {
+		return slog.Value{}
+	}
+
+	attrs := []slog.Attr{
+		slog.String("platform", string(r.Platform)),
+	}
+	if r.VideoID != "" {
+		attrs = append(attrs, slog.String("video_id", r.VideoID))
+	}
+	if r.URL != "" {
+		attrs = append(attrs, slog.String("url", RedactURL(r.URL)))
+	}
+	if r.Cookie != "" {
+		attrs = append(attrs, slog.String("cookie_id", cookieKey(r.Cookie)))
+	}
+	if r.Proxy != "" {
+		attrs = append(attrs, slog.String("proxy", RedactURL(r.Proxy)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// RedactingHandler 对日志属性自动脱敏的slog.Handler
+//
+// 属性名包含cookie、token、password等敏感词时值被替换为[REDACTED]；
+// 其余字符串值中的URL凭据和敏感字段同样会被隐去。
+type RedactingHandler struct {
+	handler slog.Handler
+}
+
+// NewRedactingHandler 包装slog.Handler，输出前对属性脱敏
+func NewRedactingHandler(handler slog.Handler) *RedactingHandler {
+	if h, ok := handler.(*RedactingHandler); ok {
+		return h
+	}
+	return &RedactingHandler{handler: handler}
+}
+
+// Enabled 实现slog.Handler
+func (h *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle 实现slog.Handler
+func (h *RedactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redactedRecord := slog.NewRecord(record.Time, record.Level, RedactText(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redactedRecord.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return h.handler.Handle(ctx, redactedRecord)
+}
+
+// WithAttrs 实现slog.Handler
+func (h *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redactedAttrs[i] = redactAttr(attr)
+	}
+	return &RedactingHandler{handler: h.handler.WithAttrs(redactedAttrs)}
+}
+
+// WithGroup 实现slog.Handler
+func (h *RedactingHandler) WithGroup(name string) slog.Handler {
+	return &RedactingHandler{handler: h.handler.WithGroup(name)}
+}
+
+// redactAttr 对单个属性脱敏，分组属性递归处理
+func redactAttr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		group := attr.Value.Group()
+		redactedGroup := make([]slog.Attr, len(group))
+		for i, a := range group {
+			redactedGroup[i] = redactAttr(a)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redactedGroup...)}
+	}
+
+	if IsSensitiveKey(attr.Key) && !strings.HasSuffix(strings.ToLower(attr.Key), "_id") {
+		return slog.String(attr.Key, redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, RedactText(attr.Value.String()))
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, RedactText(err.Error()))
+		}
+	}
+	return attr
+}
+
+// discardHandler 丢弃全部日志的slog.Handler
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// discardLogger 未设置日志时使用的空日志
+var discardLogger = slog.New(discardHandler{})
+
+// loggerKey 上下文中日志的键
+type loggerKey struct{}
+
+// withLogger 将日志放入上下文，供解析器记录后端请求
+func withLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	if logger == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFromContext 获取上下文中的日志，未设置时返回丢弃全部输出的日志
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return discardLogger
+}
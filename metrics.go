@@ -0,0 +1,309 @@
+package videosdk
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 指标名称
+const (
+	MetricParseRequests   = "videosdk_parse_requests_total"             // 解析请求数{platform}
+	MetricParseErrors     = "videosdk_parse_errors_total"               // 解析失败数{platform,code}
+	MetricParseDuration   = "videosdk_parse_duration_seconds"           // 解析耗时{platform}
+	MetricBackendRequests = "videosdk_backend_requests_total"           // 后端请求数{platform,backend,status}
+	MetricBackendDuration = "videosdk_backend_request_duration_seconds" // 后端请求耗时{platform,backend}
+	MetricCacheHits       = "videosdk_cache_hits_total"                 // 缓存命中数{platform}
+	MetricCacheMisses     = "videosdk_cache_misses_total"               // 缓存未命中数{platform}
+	MetricRetries         = "videosdk_retries_total"                    // 重试（切换后端）次数{platform,backend}
+	MetricSchemaDrift     = "videosdk_schema_drift_total"               // 后端响应结构问题数{schema,field,kind}
+)
+
+// metricKind 指标类型
+type metricKind string
+
+const (
+	metricCounter   metricKind = "counter"
+	metricHistogram metricKind = "histogram"
+)
+
+// metricDefs 指标定义
+var metricDefs = map[string]struct {
+	kind metricKind
+	help string
+}{
+	MetricParseRequests:   {metricCounter, "Total number of parse requests."},
+	MetricParseErrors:     {metricCounter, "Total number of failed parse requests by error code."},
+	MetricParseDuration:   {metricHistogram, "Parse request latency in seconds."},
+	MetricBackendRequests: {metricCounter, "Total number of backend requests by HTTP status."},
+	MetricBackendDuration: {metricHistogram, "Backend request latency in seconds."},
+	MetricCacheHits:       {metricCounter, "Total number of cache hits."},
+	MetricCacheMisses:     {metricCounter, "Total number of cache misses."},
+	MetricRetries:         {metricCounter, "Total number of retries on another backend."},
+	MetricSchemaDrift:     {metricCounter, "Total number of backend responses deviating from the expected schema."},
+}
+
+// DefaultBuckets 耗时直方图的默认分桶（秒）
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// seriesKey 指标序列的键
+type seriesKey struct {
+	name   string
+	labels string // 已格式化的标签，如platform="douyin",code="timeout"
+}
+
+// series 单个指标序列的值
+type series struct {
+	value   float64  // 计数器的值
+	buckets []uint64 // 直方图各分桶的计数（非累计）
+	sum     float64  // 直方图观测值之和
+	count   uint64   // 直方图观测次数
+}
+
+// Metrics 内存中的Prometheus风格指标，可通过WritePrometheus或作为http.Handler导出
+//
+// 所有方法对nil接收者安全，未设置指标时调用不产生任何效果。
+type Metrics struct {
+	mu      sync.Mutex
+	buckets []float64
+	series  map[seriesKey]*series
+}
+
+// NewMetrics 创建指标集合
+func NewMetrics() *Metrics {
+	return &Metrics{
+		buckets: DefaultBuckets,
+		series:  make(map[seriesKey]*series),
+	}
+}
+
+// SetBuckets 设置耗时直方图的分桶（秒，升序），已记录的直方图会被清空
+func (m *Metrics) SetBuckets(buckets []float64) {
+	if m == nil {
+		return
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.buckets = sorted
+	for key := range m.series {
+		if metricDefs[key.name].kind == metricHistogram {
+			delete(m.series, key)
+		}
+	}
+}
+
+// ObserveParse 记录一次解析请求
+func (m *Metrics) ObserveParse(platform Platform, code ErrorCode, duration time.Duration) {
+	m.add(MetricParseRequests, 1, "platform", string(platform))
+	if code != "" {
+		m.add(MetricParseErrors, 1, "platform", string(platform), "code", string(code))
+	}
+	m.observe(MetricParseDuration, duration.Seconds(), "platform", string(platform))
+}
+
+// ObserveBackend 记录一次后端请求，status为HTTP状态码，请求未得到响应时为0
+func (m *Metrics) ObserveBackend(platform Platform, backend string, status int, duration time.Duration) {
+	statusLabel := "error"
+	if status > 0 {
+		statusLabel = strconv.Itoa(status)
+	}
+	m.add(MetricBackendRequests, 1, "platform", string(platform), "backend", backend, "status", statusLabel)
+	m.observe(MetricBackendDuration, duration.Seconds(), "platform", string(platform), "backend", backend)
+}
+
+// CacheHit 记录一次缓存命中，供缓存中间件调用
+func (m *Metrics) CacheHit(platform Platform) {
+	m.add(MetricCacheHits, 1, "platform", string(platform))
+}
+
+// CacheMiss 记录一次缓存未命中，供缓存中间件调用
+func (m *Metrics) CacheMiss(platform Platform) {
+	m.add(MetricCacheMisses, 1, "platform", string(platform))
+}
+
+// Retry 记录一次切换到其他后端的重试
+func (m *Metrics) Retry(platform Platform, backend string) {
+	m.add(MetricRetries, 1, "platform", string(platform), "backend", backend)
+}
+
+// SchemaDrift 记录一次后端响应结构问题
+func (m *Metrics) SchemaDrift(schema, field string, kind SchemaIssueKind) {
+	m.add(MetricSchemaDrift, 1, "schema", schema, "field", field, "kind", string(kind))
+}
+
+// Value 获取指标序列的当前值：计数器返回计数，直方图返回观测次数
+//
+// labels为成对的标签名和标签值，顺序需与记录时一致。
+func (m *Metrics) Value(name string, labels ...string) float64 {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.series[seriesKey{name: name, labels: formatLabels(labels)}]
+	if !ok {
+		return 0
+	}
+	if metricDefs[name].kind == metricHistogram {
+		return float64(s.count)
+	}
+	return s.value
+}
+
+// WritePrometheus 以Prometheus文本格式输出全部指标
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]seriesKey, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].labels < keys[j].labels
+	})
+
+	bw := bufio.NewWriter(w)
+	var current string
+	for _, key := range keys {
+		def := metricDefs[key.name]
+		if key.name != current {
+			current = key.name
+			fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", key.name, def.help, key.name, def.kind)
+		}
+
+		s := m.series[key]
+		if def.kind == metricCounter {
+			fmt.Fprintf(bw, "%s%s %s\n", key.name, braced(key.labels), formatFloat(s.value))
+			continue
+		}
+
+		var cumulative uint64
+		for i, bound := range m.buckets {
+			cumulative += s.buckets[i]
+			fmt.Fprintf(bw, "%s_bucket%s %d\n", key.name, braced(joinLabels(key.labels, `le="`+formatFloat(bound)+`"`)), cumulative)
+		}
+		fmt.Fprintf(bw, "%s_bucket%s %d\n", key.name, braced(joinLabels(key.labels, `le="+Inf"`)), s.count)
+		fmt.Fprintf(bw, "%s_sum%s %s\n", key.name, braced(key.labels), formatFloat(s.sum))
+		fmt.Fprintf(bw, "%s_count%s %d\n", key.name, braced(key.labels), s.count)
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP 以Prometheus文本格式响应指标抓取请求
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WritePrometheus(w)
+}
+
+// add 计数器增加指定值
+func (m *Metrics) add(name string, value float64, labels ...string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.get(name, labels).value += value
+}
+
+// observe 直方图记录一个观测值
+func (m *Metrics) observe(name string, value float64, labels ...string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.get(name, labels)
+	s.sum += value
+	s.count++
+	for i, bound := range m.buckets {
+		if value <= bound {
+			s.buckets[i]++
+			break
+		}
+	}
+}
+
+// get 获取或创建指标序列（调用方需持有锁）
+func (m *Metrics) get(name string, labels []string) *series {
+	key := seriesKey{name: name, labels: formatLabels(labels)}
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{}
+		if metricDefs[name].kind == metricHistogram {
+			s.buckets = make([]uint64, len(m.buckets))
+		}
+		m.series[key] = s
+	}
+	return s
+}
+
+// metricsKey 上下文中指标集合的键
+type metricsKey struct{}
+
+// withMetrics 将指标集合放入上下文，供解析器记录后端请求
+func withMetrics(ctx context.Context, m *Metrics) context.Context {
+	if m == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, metricsKey{}, m)
+}
+
+// MetricsFromContext 获取上下文中的指标集合，未设置时返回nil（可以直接调用其方法）
+func MetricsFromContext(ctx context.Context) *Metrics {
+	m, _ := ctx.Value(metricsKey{}).(*Metrics)
+	return m
+}
+
+// formatLabels 将成对的标签名和标签值格式化为Prometheus标签
+func formatLabels(labels []string) string {
+	parts := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		parts = append(parts, labels[i]+`="`+escapeLabel(labels[i+1])+`"`)
+	}
+	return strings.Join(parts, ",")
+}
+
+// escapeLabel 转义标签值中的反斜杠、双引号和换行
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// joinLabels 拼接两组已格式化的标签
+func joinLabels(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "," + b
+}
+
+// braced 为非空标签加上花括号
+func braced(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+// formatFloat 按Prometheus的习惯格式化数值
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,94 @@
+package videosdk
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// ParseFunc 解析函数，与Parser.ParseVideo签名一致
+type ParseFunc func(ctx context.Context, req *ParseRequest) (*VideoInfo, error)
+
+// Middleware 解析中间件，包装下一层解析函数
+//
+// 中间件可以在调用next前修改请求、直接返回结果（如缓存命中）或拒绝请求，
+// 也可以在next返回后修改或替换结果。
+type Middleware func(next ParseFunc) ParseFunc
+
+// RequestHook 解析前执行的钩子，可以修改请求，返回错误时中止解析
+type RequestHook func(ctx context.Context, req *ParseRequest) error
+
+// ResponseHook 解析成功后执行的钩子，可以修改视频信息，返回错误时解析失败
+type ResponseHook func(ctx context.Context, req *ParseRequest, info *VideoInfo) error
+
+// Chain 将多个中间件组合为一个，第一个中间件位于最外层
+func Chain(middleware ...Middleware) Middleware {
+	return func(next ParseFunc) ParseFunc {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			if middleware[i] != nil {
+				next = middleware[i](next)
+			}
+		}
+		return next
+	}
+}
+
+// BeforeParse 将解析前钩子包装为中间件
+func BeforeParse(hook RequestHook) Middleware {
+	return func(next ParseFunc) ParseFunc {
+		return func(ctx context.Context, req *ParseRequest) (*VideoInfo, error) {
+			if err := hook(ctx, req); err != nil {
+				return nil, err
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// AfterParse 将解析后钩子包装为中间件，只在解析成功时执行
+func AfterParse(hook ResponseHook) Middleware {
+	return func(next ParseFunc) ParseFunc {
+		return func(ctx context.Context, req *ParseRequest) (*VideoInfo, error) {
+			info, err := next(ctx, req)
+			if err != nil || info == nil {
+				return info, err
+			}
+			if err := hook(ctx, req, info); err != nil {
+				return nil, err
+			}
+			return info, nil
+		}
+	}
+}
+
+// RewriteDownloadHosts 创建按主机名替换下载链接的解析后钩子，例如将CDN域名替换为自建镜像
+//
+// hosts的键为原主机名，值为替换后的主机名（可带端口）。
+func RewriteDownloadHosts(hosts map[string]string) ResponseHook {
+	normalized := make(map[string]string, len(hosts))
+	for from, to := range hosts {
+		normalized[strings.ToLower(from)] = to
+	}
+
+	return func(ctx context.Context, req *ParseRequest, info *VideoInfo) error {
+		for i, item := range info.Downloads {
+			info.Downloads[i].URL = rewriteHost(item.URL, normalized)
+		}
+		return nil
+	}
+}
+
+// rewriteHost 替换URL的主机名，无法解析或未匹配时原样返回
+func rewriteHost(raw string, hosts map[string]string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	host, ok := hosts[strings.ToLower(u.Hostname())]
+	if !ok {
+		return raw
+	}
+	u.Host = host
+	return u.String()
+}
@@ -0,0 +1,81 @@
+// Package otel 将SDK的追踪接口适配到OpenTelemetry
+//
+// 核心包只定义videosdk.Tracer接口，不依赖OpenTelemetry；需要接入OTel时使用本包包装一个trace.Tracer：
+//
+//	tracer := otel.NewTracer(otelapi.Tracer("github.com/caojianfei/parser"))
+//	sdk.SetTracer(tracer)
+//
+// Span通过OTel的上下文传播，解析器创建的子Span会挂在调用方的Span下。
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	videosdk "github.com/caojianfei/parser"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer 基于OpenTelemetry的追踪器
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer 创建OpenTelemetry追踪器
+func NewTracer(tracer trace.Tracer) *Tracer {
+	return &Tracer{tracer: tracer}
+}
+
+// Start 开始一个操作，实现videosdk.Tracer接口
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...videosdk.Attribute) (context.Context, videosdk.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(attributes(attrs)...))
+	return ctx, &otelSpan{span: span}
+}
+
+// otelSpan 包装OpenTelemetry的Span
+type otelSpan struct {
+	span trace.Span
+}
+
+// SetAttributes 设置属性
+func (s *otelSpan) SetAttributes(attrs ...videosdk.Attribute) {
+	s.span.SetAttributes(attributes(attrs)...)
+}
+
+// RecordError 记录错误并将Span状态设为Error
+func (s *otelSpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End 结束操作
+func (s *otelSpan) End() {
+	s.span.End()
+}
+
+// attributes 转换属性，不支持的类型按字符串记录
+func attributes(attrs []videosdk.Attribute) []attribute.KeyValue {
+	converted := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			converted = append(converted, attribute.String(attr.Key, v))
+		case int:
+			converted = append(converted, attribute.Int(attr.Key, v))
+		case int64:
+			converted = append(converted, attribute.Int64(attr.Key, v))
+		case float64:
+			converted = append(converted, attribute.Float64(attr.Key, v))
+		case bool:
+			converted = append(converted, attribute.Bool(attr.Key, v))
+		default:
+			converted = append(converted, attribute.String(attr.Key, fmt.Sprint(v)))
+		}
+	}
+	return converted
+}
@@ -0,0 +1,44 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	videosdk "github.com/caojianfei/parser"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := NewTracer(provider.Tracer("test"))
+
+	ctx, parent := tracer.Start(context.Background(), "parent", videosdk.Attribute{Key: "platform", Value: "douyin"})
+	_, child := tracer.Start(ctx, "child")
+	child.SetAttributes(videosdk.Attribute{Key: "http.status_code", Value: 502})
+	child.RecordError(errors.New("bad gateway"))
+	child.End()
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	gotChild, gotParent := spans[0], spans[1]
+	if gotChild.Parent().SpanID() != gotParent.SpanContext().SpanID() {
+		t.Errorf("child span is not a child of parent")
+	}
+	if gotChild.Status().Code != codes.Error {
+		t.Errorf("child status = %v, want Error", gotChild.Status().Code)
+	}
+	if attrs := gotChild.Attributes(); len(attrs) != 1 || attrs[0] != attribute.Int("http.status_code", 502) {
+		t.Errorf("child attributes = %v", attrs)
+	}
+	if attrs := gotParent.Attributes(); len(attrs) != 1 || attrs[0] != attribute.String("platform", "douyin") {
+		t.Errorf("parent attributes = %v", attrs)
+	}
+}
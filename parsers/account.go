@@ -0,0 +1,91 @@
+package parsers
+
+import (
+	"fmt"
+	"strings"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// loginMessages 后端在Cookie失效、需要登录时返回的消息片段
+//
+// 后端没有区分失败原因的状态码，只能按消息识别。这里只收录后端固定的提示语，
+// 不使用"cookie"、"login"这类宽泛的词，避免参数错误等消息被误判为Cookie失效。
+var loginMessages = []string{
+	"请检查 Cookie 是否有效或重新登录",
+	"Cookie 已失效",
+	"Cookie 已过期",
+	"登录已过期",
+	"登录状态已失效",
+	"请先登录",
+	"需要登录",
+	"login required",
+	"not logged in",
+	"cookie expired",
+}
+
+// verificationMessages 后端在触发平台验证时返回的消息片段
+var verificationMessages = []string{
+	"滑块验证",
+	"安全验证",
+	"人机验证",
+	"验证码",
+	"触发风控",
+	"captcha",
+	"verification required",
+	"verify_check",
+}
+
+// proxyMessages 后端在代理连接失败时返回的消息片段
+//
+// 消息中出现"代理"或"proxy"不一定是代理故障（例如回显的请求参数），只收录明确的连接失败提示。
+var proxyMessages = []string{
+	"代理不可用",
+	"代理连接失败",
+	"代理服务器连接失败",
+	"代理认证失败",
+	"proxy error",
+	"proxy connection failed",
+	"cannot connect to proxy",
+	"unable to connect to proxy",
+	"proxy authentication required",
+}
+
+// messageError 根据后端返回的消息识别Cookie失效、平台验证或代理故障，无法识别时返回nil
+func messageError(message string) error {
+	if err := accountError(message); err != nil {
+		return err
+	}
+
+	if containsMessage(message, proxyMessages) {
+		return videosdk.NewError(videosdk.ErrCodeProxyFailed, fmt.Errorf("代理不可用: %s", message))
+	}
+	return nil
+}
+
+// accountError 根据后端返回的消息识别Cookie失效或平台验证，无法识别时返回nil
+func accountError(message string) error {
+	if containsMessage(message, verificationMessages) {
+		return videosdk.NewError(videosdk.ErrCodeVerificationRequired, fmt.Errorf("平台要求验证: %s", message))
+	}
+	if containsMessage(message, loginMessages) {
+		return videosdk.NewError(videosdk.ErrCodeLoginRequired, fmt.Errorf("Cookie失效，平台要求登录: %s", message))
+	}
+	return nil
+}
+
+// containsMessage 判断消息是否包含任一片段，忽略大小写和空白
+func containsMessage(message string, fragments []string) bool {
+	normalized := normalizeMessage(message)
+	for _, fragment := range fragments {
+		if strings.Contains(normalized, normalizeMessage(fragment)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeMessage 转为小写并去除空白，兼容后端不同版本在中英文之间是否加空格
+func normalizeMessage(message string) string {
+	return strings.Join(strings.Fields(strings.ToLower(message)), "")
+}
@@ -0,0 +1,57 @@
+package parsers
+
+import (
+	"testing"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+func TestAccountError(t *testing.T) {
+	tests := []struct {
+		message string
+		want    videosdk.ErrorCode
+	}{
+		{"获取数据失败，请检查 Cookie 是否有效或重新登录！", videosdk.ErrCodeLoginRequired},
+		{"获取小红书作品数据失败，请检查Cookie是否有效或重新登录", videosdk.ErrCodeLoginRequired},
+		{"登录已过期", videosdk.ErrCodeLoginRequired},
+		{"Login Required", videosdk.ErrCodeLoginRequired},
+		{"获取作品数据失败，触发平台滑块验证，请稍后重试", videosdk.ErrCodeVerificationRequired},
+		{"请输入验证码", videosdk.ErrCodeVerificationRequired},
+		{"CAPTCHA required", videosdk.ErrCodeVerificationRequired},
+		{"获取数据成功！", ""},
+		{"获取数据失败！", ""},
+		{"请求参数验证失败", ""},
+		{"cookie 参数格式错误", ""},
+		{"verify ssl certificate failed", ""},
+		{"login page redirect", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		err := accountError(tt.message)
+		if got := videosdk.ErrorCodeOf(err); got != tt.want {
+			t.Errorf("accountError(%q) = %v, want code %q", tt.message, err, tt.want)
+		}
+	}
+}
+
+func TestMessageError(t *testing.T) {
+	tests := []struct {
+		message string
+		want    videosdk.ErrorCode
+	}{
+		{"获取数据失败，代理连接失败", videosdk.ErrCodeProxyFailed},
+		{"Cannot connect to proxy.", videosdk.ErrCodeProxyFailed},
+		{"获取数据失败，请检查 Cookie 是否有效或重新登录！", videosdk.ErrCodeLoginRequired},
+		{"参数proxy格式错误", ""},
+		{"未使用代理", ""},
+		{"获取数据失败！", ""},
+	}
+
+	for _, tt := range tests {
+		err := messageError(tt.message)
+		if got := videosdk.ErrorCodeOf(err); got != tt.want {
+			t.Errorf("messageError(%q) = %v, want code %q", tt.message, err, tt.want)
+		}
+	}
+}
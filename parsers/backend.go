@@ -0,0 +1,153 @@
+package parsers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/go-resty/resty/v2"
+	"github.com/tidwall/gjson"
+)
+
+// backendError 将请求后端服务时的网络错误归类为后端不可用
+func backendError(err error) error {
+	return videosdk.NewError(videosdk.ErrCodeBackendUnavailable, err)
+}
+
+// statusError 根据后端返回的非200状态码构造错误，5xx视为后端不可用
+func statusError(status int, message string) error {
+	err := fmt.Errorf("%s，状态码: %d", message, status)
+	if status >= http.StatusInternalServerError {
+		return backendError(err)
+	}
+	return err
+}
+
+// checkBackend 检查后端服务是否可达，返回任意非5xx响应即视为健康
+func checkBackend(ctx context.Context, client *resty.Client, baseURL string) error {
+	resp, err := client.R().SetContext(ctx).Get(baseURL + "/")
+	if err != nil {
+		return backendError(fmt.Errorf("后端服务不可达: %w", err))
+	}
+	if resp.StatusCode() >= http.StatusInternalServerError {
+		return statusError(resp.StatusCode(), "后端服务异常")
+	}
+	return nil
+}
+
+// excerptLimit 日志中记录的后端响应片段的最大字节数
+const excerptLimit = 512
+
+// postBackend 向后端服务发送POST请求，并记录追踪Span、后端请求指标和日志
+func postBackend(ctx context.Context, platform videosdk.Platform, client *resty.Client, baseURL, path string, body interface{}) (*resty.Response, error) {
+	ctx, span := videosdk.StartSpan(ctx, "backend.request",
+		videosdk.Attribute{Key: "platform", Value: string(platform)},
+		videosdk.Attribute{Key: "backend", Value: baseURL},
+		videosdk.Attribute{Key: "http.route", Value: path},
+	)
+	defer span.End()
+
+	log := videosdk.LoggerFromContext(ctx)
+	log.DebugContext(ctx, "backend request",
+		slog.String("platform", string(platform)),
+		slog.String("backend", baseURL),
+		slog.String("path", path),
+	)
+
+	start := time.Now()
+	resp, err := client.R().
+		SetContext(ctx).
+		SetBody(body).
+		Post(baseURL + path)
+
+	status := 0
+	if err == nil {
+		status = resp.StatusCode()
+		span.SetAttributes(videosdk.Attribute{Key: "http.status_code", Value: status})
+	}
+	elapsed := time.Since(start)
+	videosdk.MetricsFromContext(ctx).ObserveBackend(platform, baseURL, status, elapsed)
+
+	attrs := []any{
+		slog.String("platform", string(platform)),
+		slog.String("backend", baseURL),
+		slog.String("path", path),
+		slog.Duration("elapsed", elapsed),
+	}
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		log.WarnContext(ctx, "backend request failed", append(attrs, slog.Any("error", err))...)
+	case status != http.StatusOK:
+		if status >= http.StatusInternalServerError {
+			span.RecordError(fmt.Errorf("backend returned status %d", status))
+		}
+		log.WarnContext(ctx, "backend returned error status",
+			append(attrs, slog.Int("status", status), slog.String("body", videosdk.Excerpt(resp.Body(), excerptLimit)))...)
+	default:
+		log.DebugContext(ctx, "backend response", append(attrs, slog.Int("status", status))...)
+	}
+
+	return resp, err
+}
+
+// decodeResponse 解析后端返回的JSON，响应体被截断或不是合法JSON时视为后端不可用
+func decodeResponse(resp *resty.Response) (gjson.Result, error) {
+	body := resp.Body()
+	if !gjson.ValidBytes(body) {
+		return gjson.Result{}, backendError(fmt.Errorf("后端返回的响应不是合法的JSON（%d字节）", len(body)))
+	}
+	return gjson.ParseBytes(body), nil
+}
+
+// rawData 复制响应中的data字段作为原始数据，不包含后端回显的Cookie等请求参数
+func rawData(result gjson.Result) json.RawMessage {
+	data := result.Get("data")
+	if !data.Exists() {
+		return nil
+	}
+	return json.RawMessage(data.Raw)
+}
+
+// logRejectedResponse 记录无法解析的后端响应片段，便于排查后端返回格式的问题
+func logRejectedResponse(ctx context.Context, platform videosdk.Platform, resp *resty.Response, err error) {
+	videosdk.LoggerFromContext(ctx).WarnContext(ctx, "backend response rejected",
+		slog.String("platform", string(platform)),
+		slog.String("backend", resp.Request.URL),
+		slog.String("body", videosdk.Excerpt(resp.Body(), excerptLimit)),
+		slog.Any("error", err),
+	)
+}
+
+// resolveRequestURL 解析请求中的短链接，返回替换为完整链接的请求副本
+func resolveRequestURL(ctx context.Context, req *videosdk.ParseRequest) (*videosdk.ParseRequest, error) {
+	if req.URL == "" {
+		return req, nil
+	}
+	resolved, err := videosdk.ResolveShortLink(ctx, req.URL, req.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("解析短链接失败: %w", err)
+	}
+	if resolved == req.URL {
+		return req, nil
+	}
+	withURL := *req
+	withURL.URL = resolved
+	return &withURL, nil
+}
+
+// extractCanonicalID 通过规范化链接提取平台的作品ID
+func extractCanonicalID(platform videosdk.Platform, url string) (string, error) {
+	canonical, err := videosdk.Canonicalize(url)
+	if err != nil || canonical.Platform != platform {
+		return "", fmt.Errorf("无法从URL中提取视频ID: %s", url)
+	}
+	if canonical.Short {
+		return "", fmt.Errorf("短链接需要先解析才能提取视频ID: %s", url)
+	}
+	return canonical.ID, nil
+}
@@ -0,0 +1,665 @@
+package parsers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/go-resty/resty/v2"
+	"github.com/tidwall/gjson"
+	"gopkg.in/yaml.v3"
+)
+
+// ParserConfig 配置驱动解析器的映射配置，可以用JSON或YAML编写
+//
+//	platform: bilibili
+//	base_url: http://localhost:5558
+//	endpoint: /bilibili/detail
+//	body:
+//	  url: "{{target}}"
+//	  cookie: "{{cookie}}"
+//	  source: "{{source}}"
+//	data: data
+//	fields:
+//	  id: {path: bvid, required: true}
+//	  type: {path: type, map: {视频: video, 图集: image}}
+//	  stats.play_count: {path: stat.view}
+//	  create_time: {path: pubdate}
+type ParserConfig struct {
+	Platform        videosdk.Platform       `json:"platform"`         // 平台
+	BaseURL         string                  `json:"base_url"`         // 后端服务地址
+	Endpoint        string                  `json:"endpoint"`         // 作品详情接口路径，使用POST请求
+	Headers         map[string]string       `json:"headers"`          // 额外的请求头
+	Timeout         string                  `json:"timeout"`          // 请求超时时间，如"30s"，默认30s
+	Body            map[string]interface{}  `json:"body"`             // 请求体模板，占位符见bodyPlaceholders
+	IDPattern       string                  `json:"id_pattern"`       // 从URL提取作品ID的正则表达式，第一个分组为ID；为空时直接使用URL
+	Data            string                  `json:"data"`             // 作品数据在响应中的gjson路径，默认"data"
+	Message         string                  `json:"message"`          // 后端消息的gjson路径，默认"message"，用于识别Cookie失效等错误
+	SuccessContains string                  `json:"success_contains"` // 成功时消息包含的文本，为空时不检查
+	Fields          map[string]FieldMapping `json:"fields"`           // VideoInfo字段到响应字段的映射，键见configTargets
+}
+
+// FieldMapping 单个VideoInfo字段的映射规则
+type FieldMapping struct {
+	Path      string            `json:"path"`      // 相对作品数据的gjson路径
+	Fallback  []string          `json:"fallback"`  // Path不存在或为空时依次尝试的路径
+	Transform string            `json:"transform"` // 值转换，见configTransforms；VideoInfo字段按类型自动选择，extra字段默认为字符串
+	Map       map[string]string `json:"map"`       // 枚举映射，如{"视频": "video"}，未匹配时使用Default
+	Default   string            `json:"default"`   // 字段缺失或枚举未匹配时的默认值
+	Separator string            `json:"separator"` // 列表字段是字符串时的分隔符，为空时按空白分隔
+	Required  bool              `json:"required"`  // 是否为必需字段，缺失时按结构校验模式处理
+}
+
+// 值转换
+const (
+	transformString   = "string"   // 字符串
+	transformInt      = "int"      // 整数
+	transformCount    = "count"    // 统计数量，支持"1.2万"等格式
+	transformTime     = "time"     // 时间，支持时间戳和常见时间格式
+	transformDuration = "duration" // 时长，支持毫秒数和"HH:MM:SS"
+	transformList     = "list"     // 字符串列表，数组或按分隔符拆分的字符串
+	transformRaw      = "raw"      // 原始JSON值，只用于extra
+)
+
+// configTransforms 支持的值转换
+var configTransforms = map[string]bool{
+	transformString: true, transformInt: true, transformCount: true, transformTime: true,
+	transformDuration: true, transformList: true, transformRaw: true,
+}
+
+// configTargets 可映射的VideoInfo字段及其默认转换，另外支持"extra.<名称>"
+var configTargets = map[string]string{
+	"id":                    transformString,
+	"title":                 transformString,
+	"description":           transformString,
+	"type":                  transformString,
+	"url":                   transformString,
+	"create_time":           transformTime,
+	"update_time":           transformTime,
+	"duration":              transformDuration,
+	"downloads":             transformList,
+	"images":                transformList,
+	"cover_url":             transformString,
+	"width":                 transformInt,
+	"height":                transformInt,
+	"author.uid":            transformString,
+	"author.sec_uid":        transformString,
+	"author.unique_id":      transformString,
+	"author.nickname":       transformString,
+	"author.avatar":         transformString,
+	"author.signature":      transformString,
+	"author.age":            transformInt,
+	"author.follower_count": transformCount,
+	"stats.play_count":      transformCount,
+	"stats.like_count":      transformCount,
+	"stats.comment_count":   transformCount,
+	"stats.share_count":     transformCount,
+	"stats.collect_count":   transformCount,
+	"music.id":              transformString,
+	"music.title":           transformString,
+	"music.author":          transformString,
+	"music.url":             transformString,
+	"tags":                  transformList,
+}
+
+// bodyPlaceholders 请求体模板中的占位符
+//
+// 字符串值恰好是一个占位符时保留原类型（如{{source}}为布尔值），否则按文本替换。
+var bodyPlaceholders = []string{"{{target}}", "{{url}}", "{{video_id}}", "{{cookie}}", "{{proxy}}", "{{source}}"}
+
+// LoadParserConfig 读取映射配置文件，支持JSON和YAML
+func LoadParserConfig(path string) (*ParserConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取解析器配置失败: %w", err)
+	}
+	cfg, err := ParseParserConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ParseParserConfig 解析JSON或YAML格式的映射配置
+func ParseParserConfig(data []byte) (*ParserConfig, error) {
+	// YAML是JSON的超集，统一按YAML读取后转为JSON，配置结构只需要json标签
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("解析解析器配置失败: %w", err)
+	}
+	normalized, err := json.Marshal(normalizeYAML(doc))
+	if err != nil {
+		return nil, fmt.Errorf("解析解析器配置失败: %w", err)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(normalized)))
+	decoder.DisallowUnknownFields()
+	var cfg ParserConfig
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("解析解析器配置失败: %w", err)
+	}
+	return &cfg, nil
+}
+
+// normalizeYAML 将YAML中非字符串键的映射（如枚举映射中的数字键）转为字符串键，便于转为JSON
+func normalizeYAML(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			normalized[fmt.Sprint(k)] = normalizeYAML(item)
+		}
+		return normalized
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = normalizeYAML(item)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = normalizeYAML(item)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// Validate 检查映射配置
+func (c *ParserConfig) Validate() error {
+	var errs []error
+	if c.Platform == "" {
+		errs = append(errs, errors.New("platform不能为空"))
+	}
+	if c.BaseURL == "" {
+		errs = append(errs, errors.New("base_url不能为空"))
+	}
+	if !strings.HasPrefix(c.Endpoint, "/") {
+		errs = append(errs, fmt.Errorf("endpoint必须以/开头: %q", c.Endpoint))
+	}
+	if c.Timeout != "" {
+		if _, err := time.ParseDuration(c.Timeout); err != nil {
+			errs = append(errs, fmt.Errorf("无效的timeout %q: %w", c.Timeout, err))
+		}
+	}
+	if c.IDPattern != "" {
+		if re, err := regexp.Compile(c.IDPattern); err != nil {
+			errs = append(errs, fmt.Errorf("无效的id_pattern: %w", err))
+		} else if re.NumSubexp() < 1 {
+			errs = append(errs, errors.New("id_pattern需要一个分组来匹配作品ID"))
+		}
+	}
+	if _, ok := c.Fields["id"]; !ok {
+		errs = append(errs, errors.New("fields中必须映射id"))
+	}
+
+	for _, target := range c.targets() {
+		mapping := c.Fields[target]
+		defaultTransform, known := configTargets[target]
+		if !known && !strings.HasPrefix(target, "extra.") {
+			errs = append(errs, fmt.Errorf("未知的字段%q", target))
+		}
+		// VideoInfo字段的类型是固定的，只有extra字段可以选择转换
+		if known && mapping.Transform != "" && mapping.Transform != defaultTransform {
+			errs = append(errs, fmt.Errorf("字段%q只能使用%s转换", target, defaultTransform))
+		}
+		if mapping.Path == "" {
+			errs = append(errs, fmt.Errorf("字段%q缺少path", target))
+		}
+		if mapping.Transform != "" && !configTransforms[mapping.Transform] {
+			errs = append(errs, fmt.Errorf("字段%q使用了未知的转换%q", target, mapping.Transform))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// targets 按名称排序的映射字段，保证校验和结构问题的顺序稳定
+func (c *ParserConfig) targets() []string {
+	targets := make([]string, 0, len(c.Fields))
+	for target := range c.Fields {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// ConfigurableParser 由映射配置驱动的解析器
+//
+// 向后端POST渲染后的请求体，再按配置把作品数据中的字段映射到VideoInfo，
+// 无需编写代码即可接入新平台或适配后端返回格式的变化。
+type ConfigurableParser struct {
+	config    ParserConfig
+	client    *resty.Client
+	idPattern *regexp.Regexp
+	schema    *videosdk.Schema
+}
+
+// NewConfigurableParser 根据映射配置创建解析器，配置无效时返回错误
+func NewConfigurableParser(cfg *ParserConfig) (*ConfigurableParser, error) {
+	if cfg == nil {
+		return nil, errors.New("解析器配置不能为空")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("无效的解析器配置: %w", err)
+	}
+
+	config := *cfg
+	if config.Data == "" {
+		config.Data = "data"
+	}
+	if config.Message == "" {
+		config.Message = "message"
+	}
+
+	// 复制字段映射并补全默认转换，避免修改调用方的配置
+	config.Fields = make(map[string]FieldMapping, len(cfg.Fields))
+	for target, mapping := range cfg.Fields {
+		if mapping.Transform == "" {
+			mapping.Transform = configTargets[target]
+		}
+		config.Fields[target] = mapping
+	}
+
+	timeout := 30 * time.Second
+	if config.Timeout != "" {
+		timeout, _ = time.ParseDuration(config.Timeout)
+	}
+
+	client := resty.New()
+	client.SetTimeout(timeout)
+	client.SetHeader("Content-Type", "application/json")
+	client.SetHeader("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	client.SetHeaders(config.Headers)
+
+	p := &ConfigurableParser{
+		config: config,
+		client: client,
+		schema: &videosdk.Schema{Name: string(config.Platform) + ".config"},
+	}
+	if config.IDPattern != "" {
+		p.idPattern = regexp.MustCompile(config.IDPattern)
+	}
+	for _, target := range config.targets() {
+		if mapping := config.Fields[target]; mapping.Required {
+			p.schema.Fields = append(p.schema.Fields, videosdk.FieldSpec{
+				Path:    config.Data + "." + mapping.Path,
+				Aliases: prefixPaths(config.Data, mapping.Fallback),
+			})
+		}
+	}
+	return p, nil
+}
+
+// prefixPaths 为相对作品数据的路径加上作品数据路径
+func prefixPaths(prefix string, paths []string) []string {
+	prefixed := make([]string, len(paths))
+	for i, path := range paths {
+		prefixed[i] = prefix + "." + path
+	}
+	return prefixed
+}
+
+// BaseURL 获取后端服务地址
+func (p *ConfigurableParser) BaseURL() string {
+	return p.config.BaseURL
+}
+
+// SetTransport 设置访问后端服务的HTTP传输层
+func (p *ConfigurableParser) SetTransport(transport http.RoundTripper) {
+	p.client.SetTransport(transport)
+}
+
+// HealthCheck 检查后端服务是否可用
+func (p *ConfigurableParser) HealthCheck(ctx context.Context) error {
+	return checkBackend(ctx, p.client, p.config.BaseURL)
+}
+
+// GetPlatform 获取平台类型
+func (p *ConfigurableParser) GetPlatform() videosdk.Platform {
+	return p.config.Platform
+}
+
+// ExtractVideoID 按id_pattern从URL提取作品ID，未配置时直接返回URL
+func (p *ConfigurableParser) ExtractVideoID(url string) (string, error) {
+	if url == "" {
+		return "", fmt.Errorf("URL不能为空")
+	}
+	if p.idPattern == nil {
+		return url, nil
+	}
+	matches := p.idPattern.FindStringSubmatch(url)
+	if len(matches) < 2 || matches[1] == "" {
+		return "", fmt.Errorf("无法从URL中提取视频ID: %s", url)
+	}
+	return matches[1], nil
+}
+
+// ValidateRequest 验证请求参数
+func (p *ConfigurableParser) ValidateRequest(req *videosdk.ParseRequest) error {
+	if req.VideoID == "" && req.URL == "" {
+		return fmt.Errorf("video_id 或 url 至少需要提供一个")
+	}
+
+	if req.Platform != p.config.Platform {
+		return fmt.Errorf("平台类型不匹配，期望: %s，实际: %s", p.config.Platform, req.Platform)
+	}
+
+	return nil
+}
+
+// ParseVideo 解析视频信息
+func (p *ConfigurableParser) ParseVideo(ctx context.Context, req *videosdk.ParseRequest) (*videosdk.VideoInfo, error) {
+	if err := p.ValidateRequest(req); err != nil {
+		return nil, err
+	}
+	req, err := resolveRequestURL(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	platform := p.config.Platform
+	resp, err := postBackend(ctx, platform, p.client, p.config.BaseURL, p.config.Endpoint, p.renderBody(req))
+	if err != nil {
+		return nil, backendError(fmt.Errorf("请求%s API失败: %w", platform, err))
+	}
+
+	if resp.StatusCode() != 200 {
+		return nil, statusError(resp.StatusCode(), fmt.Sprintf("%s API请求失败", platform))
+	}
+
+	result, err := decodeResponse(resp)
+	if err != nil {
+		logRejectedResponse(ctx, platform, resp, err)
+		return nil, err
+	}
+
+	videoInfo, err := p.parseVideoData(ctx, result)
+	if err != nil {
+		logRejectedResponse(ctx, platform, resp, err)
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	// 未映射作品链接时使用请求的URL
+	if videoInfo.URL == "" {
+		videoInfo.URL = req.URL
+	}
+	if req.Source {
+		videoInfo.Raw = json.RawMessage(result.Get(p.config.Data).Raw)
+	}
+	return videoInfo, nil
+}
+
+// renderBody 用请求参数渲染请求体模板
+func (p *ConfigurableParser) renderBody(req *videosdk.ParseRequest) map[string]interface{} {
+	target := req.URL
+	if target == "" {
+		target = req.VideoID
+	}
+	values := map[string]interface{}{
+		"{{target}}":   target,
+		"{{url}}":      req.URL,
+		"{{video_id}}": req.VideoID,
+		"{{cookie}}":   req.Cookie,
+		"{{proxy}}":    req.Proxy,
+		"{{source}}":   req.Source,
+	}
+
+	pairs := make([]string, 0, len(bodyPlaceholders)*2)
+	for _, placeholder := range bodyPlaceholders {
+		pairs = append(pairs, placeholder, fmt.Sprint(values[placeholder]))
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	var render func(v interface{}) interface{}
+	render = func(v interface{}) interface{} {
+		switch value := v.(type) {
+		case string:
+			if exact, ok := values[value]; ok {
+				return exact
+			}
+			return replacer.Replace(value)
+		case map[string]interface{}:
+			rendered := make(map[string]interface{}, len(value))
+			for k, item := range value {
+				rendered[k] = render(item)
+			}
+			return rendered
+		case []interface{}:
+			rendered := make([]interface{}, len(value))
+			for i, item := range value {
+				rendered[i] = render(item)
+			}
+			return rendered
+		default:
+			return v
+		}
+	}
+	return render(p.config.Body).(map[string]interface{})
+}
+
+// parseVideoData 按映射配置解析作品数据
+func (p *ConfigurableParser) parseVideoData(ctx context.Context, result gjson.Result) (*videosdk.VideoInfo, error) {
+	message := result.Get(p.config.Message).String()
+	data := result.Get(p.config.Data)
+	if !data.IsObject() || (p.config.SuccessContains != "" && !strings.Contains(message, p.config.SuccessContains)) {
+		if err := messageError(message); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("响应中没有作品数据: %s", message)
+	}
+
+	if err := videosdk.CheckSchema(ctx, p.schema, result); err != nil {
+		return nil, err
+	}
+
+	info := &videosdk.VideoInfo{
+		Type:     videosdk.VideoTypeUnknown,
+		Platform: p.config.Platform,
+		Author:   videosdk.AuthorInfo{FollowerCount: videosdk.CountUnknown},
+		Stats: videosdk.VideoStats{
+			PlayCount:    videosdk.CountUnknown,
+			LikeCount:    videosdk.CountUnknown,
+			CommentCount: videosdk.CountUnknown,
+			ShareCount:   videosdk.CountUnknown,
+			CollectCount: videosdk.CountUnknown,
+		},
+		Tags:  []string{},
+		Extra: make(map[string]interface{}),
+	}
+
+	var downloads, images []string
+	for _, target := range p.config.targets() {
+		mapping := p.config.Fields[target]
+		value, ok := lookupField(data, mapping)
+		if !ok && mapping.Default == "" {
+			continue
+		}
+
+		if strings.HasPrefix(target, "extra.") {
+			info.Extra[strings.TrimPrefix(target, "extra.")] = convertField(value, ok, mapping)
+			continue
+		}
+
+		switch target {
+		case "downloads":
+			downloads = fieldList(value, mapping)
+		case "images":
+			images = fieldList(value, mapping)
+		case "tags":
+			info.Tags = fieldList(value, mapping)
+		default:
+			assignField(info, target, convertField(value, ok, mapping))
+		}
+	}
+
+	// 下载链接：images中的链接为图片，downloads中的链接按作品类型判断
+	mediaType := videosdk.MediaTypeVideo
+	if info.Type == videosdk.VideoTypeImage {
+		mediaType = videosdk.MediaTypeImage
+	}
+	for _, url := range downloads {
+		info.Downloads = append(info.Downloads, videosdk.DownloadItem{URL: url, Type: mediaType})
+	}
+	for _, url := range images {
+		info.Downloads = append(info.Downloads, videosdk.DownloadItem{URL: url, Type: videosdk.MediaTypeImage})
+	}
+
+	return info, nil
+}
+
+// lookupField 按Path和Fallback依次查找第一个存在且非空的值
+func lookupField(data gjson.Result, mapping FieldMapping) (gjson.Result, bool) {
+	for _, path := range append([]string{mapping.Path}, mapping.Fallback...) {
+		value := data.Get(path)
+		if value.Exists() && value.Type != gjson.Null && value.String() != "" {
+			return value, true
+		}
+	}
+	return gjson.Result{}, false
+}
+
+// fieldText 获取字段的文本值并应用枚举映射和默认值
+func fieldText(value gjson.Result, ok bool, mapping FieldMapping) string {
+	if !ok {
+		return mapping.Default
+	}
+	text := value.String()
+	if mapping.Map != nil {
+		if mapped, found := mapping.Map[text]; found {
+			return mapped
+		}
+		if mapping.Default != "" {
+			return mapping.Default
+		}
+	}
+	return text
+}
+
+// fieldList 获取列表字段，数组逐项取值，字符串按分隔符拆分
+func fieldList(value gjson.Result, mapping FieldMapping) []string {
+	items := []string{}
+	if value.IsArray() {
+		for _, item := range value.Array() {
+			if text := item.String(); text != "" {
+				items = append(items, text)
+			}
+		}
+		return items
+	}
+
+	text := value.String()
+	var parts []string
+	if mapping.Separator == "" {
+		parts = strings.Fields(text)
+	} else {
+		parts = strings.Split(text, mapping.Separator)
+	}
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+// convertField 按转换获取字段的值，未指定转换时按目标字段的类型转换，extra字段默认为字符串
+func convertField(value gjson.Result, ok bool, mapping FieldMapping) interface{} {
+	switch mapping.Transform {
+	case transformRaw:
+		if !ok {
+			return nil
+		}
+		return value.Value()
+	case transformList:
+		return fieldList(value, mapping)
+	case transformInt:
+		return int(gjson.Parse(fieldText(value, ok, mapping)).Int())
+	case transformCount:
+		if ok && value.Type == gjson.Number {
+			return parseCount(value)
+		}
+		return videosdk.ParseCount(fieldText(value, ok, mapping))
+	case transformTime:
+		return videosdk.ParseTime(fieldText(value, ok, mapping))
+	case transformDuration:
+		return videosdk.ParseDuration(fieldText(value, ok, mapping))
+	default:
+		return fieldText(value, ok, mapping)
+	}
+}
+
+// assignField 将转换后的值写入VideoInfo的目标字段
+func assignField(info *videosdk.VideoInfo, target string, value interface{}) {
+	text, _ := value.(string)
+	number, _ := value.(int)
+	count, _ := value.(videosdk.Count)
+	parsed, _ := value.(time.Time)
+	duration, _ := value.(time.Duration)
+
+	switch target {
+	case "id":
+		info.ID = text
+	case "title":
+		info.Title = text
+	case "description":
+		info.Description = text
+	case "type":
+		info.Type = videosdk.VideoType(text)
+	case "url":
+		info.URL = text
+	case "create_time":
+		info.CreateTime = parsed
+	case "update_time":
+		info.UpdateTime = parsed
+	case "duration":
+		info.Duration = duration
+	case "cover_url":
+		info.CoverURL = text
+	case "width":
+		info.Width = number
+	case "height":
+		info.Height = number
+	case "author.uid":
+		info.Author.UID = text
+	case "author.sec_uid":
+		info.Author.SecUID = text
+	case "author.unique_id":
+		info.Author.UniqueID = text
+	case "author.nickname":
+		info.Author.Nickname = text
+	case "author.avatar":
+		info.Author.Avatar = text
+	case "author.signature":
+		info.Author.Signature = text
+	case "author.age":
+		info.Author.Age = number
+	case "author.follower_count":
+		info.Author.FollowerCount = count
+	case "stats.play_count":
+		info.Stats.PlayCount = count
+	case "stats.like_count":
+		info.Stats.LikeCount = count
+	case "stats.comment_count":
+		info.Stats.CommentCount = count
+	case "stats.share_count":
+		info.Stats.ShareCount = count
+	case "stats.collect_count":
+		info.Stats.CollectCount = count
+	case "music.id":
+		info.Music.ID = text
+	case "music.title":
+		info.Music.Title = text
+	case "music.author":
+		info.Music.Author = text
+	case "music.url":
+		info.Music.URL = text
+	}
+}
@@ -0,0 +1,21 @@
+package parsers
+
+import (
+	videosdk "github.com/caojianfei/parser"
+	"github.com/tidwall/gjson"
+)
+
+// parseCount 解析响应中的数量字段，字段缺失时返回未知数量
+func parseCount(value gjson.Result) videosdk.Count {
+	switch value.Type {
+	case gjson.Number:
+		if value.Num < 0 {
+			return videosdk.CountUnknown
+		}
+		return videosdk.Count(value.Int())
+	case gjson.String:
+		return videosdk.ParseCount(value.String())
+	default:
+		return videosdk.CountUnknown
+	}
+}
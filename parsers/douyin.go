@@ -3,6 +3,7 @@ package parsers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"regexp"
 	"strings"
 	"time"
@@ -31,6 +32,21 @@ func NewDouyinParser(baseURL string) videosdk.Parser {
 	}
 }
 
+// BaseURL 获取后端服务地址
+func (p *DouyinParser) BaseURL() string {
+	return p.baseURL
+}
+
+// SetTransport 设置访问后端服务的HTTP传输层
+func (p *DouyinParser) SetTransport(transport http.RoundTripper) {
+	p.client.SetTransport(transport)
+}
+
+// HealthCheck 检查后端服务是否可用
+func (p *DouyinParser) HealthCheck(ctx context.Context) error {
+	return checkBackend(ctx, p.client, p.baseURL)
+}
+
 // GetPlatform 获取平台类型
 func (p *DouyinParser) GetPlatform() videosdk.Platform {
 	return videosdk.PlatformDouyin
@@ -57,26 +73,39 @@ func (p *DouyinParser) ExtractVideoID(url string) (string, error) {
 }
 
 // resolveShortURL 解析短链接获取完整URL
-func (p *DouyinParser) resolveShortURL(shortURL string, proxy string) (string, error) {
+func (p *DouyinParser) resolveShortURL(ctx context.Context, shortURL string, proxy string) (fullURL string, err error) {
+	ctx, span := videosdk.StartSpan(ctx, "DouyinParser.resolveShortURL",
+		videosdk.Attribute{Key: "platform", Value: string(videosdk.PlatformDouyin)},
+	)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	req := map[string]interface{}{
 		"text":  shortURL,
 		"proxy": proxy,
 	}
 
-	resp, err := p.client.R().
-		SetBody(req).
-		Post(p.baseURL + "/douyin/share")
+	resp, err := postBackend(ctx, videosdk.PlatformDouyin, p.client, p.baseURL, "/douyin/share", req)
 
 	if err != nil {
-		return "", fmt.Errorf("请求分享链接解析失败: %w", err)
+		return "", backendError(fmt.Errorf("请求分享链接解析失败: %w", err))
 	}
 
 	if resp.StatusCode() != 200 {
-		return "", fmt.Errorf("分享链接解析请求失败，状态码: %d", resp.StatusCode())
+		return "", statusError(resp.StatusCode(), "分享链接解析请求失败")
 	}
 
 	// 解析响应
-	result := gjson.ParseBytes(resp.Body())
+	result, err := decodeResponse(resp)
+	if err != nil {
+		logRejectedResponse(ctx, videosdk.PlatformDouyin, resp, err)
+		return "", err
+	}
+	if err := videosdk.CheckSchema(ctx, douyinShareSchema, result); err != nil {
+		return "", err
+	}
 	if !result.Get("url").Exists() {
 		return "", fmt.Errorf("分享链接解析响应中未找到URL")
 	}
@@ -104,10 +133,16 @@ func (p *DouyinParser) ParseVideo(ctx context.Context, req *videosdk.ParseReques
 
 	// 步骤1: 如果提供的是URL，需要先获取视频ID
 	if req.URL != "" {
+		// 优先由SDK的短链接解析器直接跟随跳转，未启用时原样返回
+		targetURL, err := videosdk.ResolveShortLink(ctx, req.URL, req.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("解析短链接失败: %w", err)
+		}
+
 		// 检查是否为短链接
-		if strings.Contains(req.URL, "v.douyin.com") {
-			// 步骤1a: 解析短链接获取完整URL
-			fullURL, err := p.resolveShortURL(req.URL, req.Proxy)
+		if strings.Contains(targetURL, "v.douyin.com") {
+			// 步骤1a: 通过后端解析短链接获取完整URL
+			fullURL, err := p.resolveShortURL(ctx, targetURL, req.Proxy)
 			if err != nil {
 				return nil, fmt.Errorf("解析短链接失败: %w", err)
 			}
@@ -118,7 +153,7 @@ func (p *DouyinParser) ParseVideo(ctx context.Context, req *videosdk.ParseReques
 			}
 		} else {
 			// 直接从完整URL提取视频ID
-			videoID, err = p.ExtractVideoID(req.URL)
+			videoID, err = p.ExtractVideoID(targetURL)
 			if err != nil {
 				return nil, fmt.Errorf("从URL提取视频ID失败: %w", err)
 			}
@@ -131,35 +166,67 @@ func (p *DouyinParser) ParseVideo(ctx context.Context, req *videosdk.ParseReques
 	}
 
 	// 步骤2: 使用视频ID获取详细数据
+	resp, result, err := p.requestDetail(ctx, req, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := videosdk.CheckSchema(ctx, douyinDetailSchema, result); err != nil {
+		logRejectedResponse(ctx, videosdk.PlatformDouyin, resp, err)
+		return nil, err
+	}
+
+	videoInfo, err := p.parseVideoData(result.Get("data"))
+	if err != nil {
+		return nil, err
+	}
+
+	// 返回同一响应中后端提取的作品数据，不再以source为true重新请求平台原始数据
+	if req.Source {
+		videoInfo.Raw = rawData(result)
+	}
+
+	return videoInfo, nil
+}
+
+// requestDetail 请求 /douyin/detail 接口，返回响应和解析后的JSON
+func (p *DouyinParser) requestDetail(ctx context.Context, req *videosdk.ParseRequest, videoID string) (*resty.Response, gjson.Result, error) {
 	requestBody := map[string]interface{}{
 		"detail_id": videoID,
 		"cookie":    req.Cookie,
 		"proxy":     req.Proxy,
-		"source":    req.Source,
+		"source":    false,
 	}
 
-	// 发送请求到 /douyin/detail 接口
-	resp, err := p.client.R().
-		SetContext(ctx).
-		SetBody(requestBody).
-		Post(p.baseURL + "/douyin/detail")
+	resp, err := postBackend(ctx, videosdk.PlatformDouyin, p.client, p.baseURL, "/douyin/detail", requestBody)
 
 	if err != nil {
-		return nil, fmt.Errorf("请求抖音API失败: %w", err)
+		return nil, gjson.Result{}, backendError(fmt.Errorf("请求抖音API失败: %w", err))
 	}
 
 	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("抖音API请求失败，状态码: %d", resp.StatusCode())
+		return nil, gjson.Result{}, statusError(resp.StatusCode(), "抖音API请求失败")
 	}
 
 	// 解析响应
-	result := gjson.ParseBytes(resp.Body())
-	if !result.Get("data").Exists() {
-		return nil, fmt.Errorf("响应中未找到data字段")
+	result, err := decodeResponse(resp)
+	if err != nil {
+		logRejectedResponse(ctx, videosdk.PlatformDouyin, resp, err)
+		return nil, gjson.Result{}, err
 	}
 
-	data := result.Get("data")
-	return p.parseVideoData(data)
+	// 作品不存在时后端返回"data": null
+	if !result.Get("data").IsObject() {
+		message := result.Get("message").String()
+		err := messageError(message)
+		if err == nil {
+			err = fmt.Errorf("响应中没有作品数据: %s", message)
+		}
+		logRejectedResponse(ctx, videosdk.PlatformDouyin, resp, err)
+		return nil, gjson.Result{}, err
+	}
+
+	return resp, result, nil
 }
 
 // parseVideoData 解析视频数据
@@ -173,7 +240,7 @@ func (p *DouyinParser) parseVideoData(data gjson.Result) (*videosdk.VideoInfo, e
 	videoInfo.Title = data.Get("desc").String()
 	videoInfo.Description = data.Get("desc").String()
 	videoInfo.URL = data.Get("share_url").String()
-	videoInfo.Duration = data.Get("duration").String()
+	videoInfo.Duration = videosdk.ParseDuration(data.Get("duration").String())
 
 	// 视频类型
 	videoType := data.Get("type").String()
@@ -188,11 +255,10 @@ func (p *DouyinParser) parseVideoData(data gjson.Result) (*videosdk.VideoInfo, e
 		videoInfo.Type = videosdk.VideoTypeUnknown
 	}
 
-	// 创建时间
-	if createTime := data.Get("create_time").String(); createTime != "" {
-		if t, err := time.Parse("2006-01-02 15:04:05", createTime); err == nil {
-			videoInfo.CreateTime = t
-		}
+	// 创建时间（优先使用时间戳，避免格式化时间的时区歧义）
+	videoInfo.CreateTime = videosdk.ParseTime(data.Get("create_timestamp").String())
+	if videoInfo.CreateTime.IsZero() {
+		videoInfo.CreateTime = videosdk.ParseTime(data.Get("create_time").String())
 	}
 
 	// 媒体信息
@@ -235,15 +301,17 @@ func (p *DouyinParser) parseVideoData(data gjson.Result) (*videosdk.VideoInfo, e
 		Nickname:  data.Get("nickname").String(),
 		Signature: data.Get("signature").String(),
 		Age:       int(data.Get("user_age").Int()),
+
+		FollowerCount: parseCount(data.Get("follower_count")),
 	}
 
 	// 统计信息
 	videoInfo.Stats = videosdk.VideoStats{
-		PlayCount:    data.Get("play_count").Int(),
-		LikeCount:    data.Get("digg_count").Int(),
-		CommentCount: data.Get("comment_count").Int(),
-		ShareCount:   data.Get("share_count").Int(),
-		CollectCount: data.Get("collect_count").Int(),
+		PlayCount:    parseCount(data.Get("play_count")),
+		LikeCount:    parseCount(data.Get("digg_count")),
+		CommentCount: parseCount(data.Get("comment_count")),
+		ShareCount:   parseCount(data.Get("share_count")),
+		CollectCount: parseCount(data.Get("collect_count")),
 	}
 
 	// 音乐信息
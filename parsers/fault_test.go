@@ -0,0 +1,142 @@
+package parsers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/parsers"
+	"github.com/caojianfei/parser/parsers/parserstest"
+	"github.com/tidwall/gjson"
+)
+
+// platformCase 指向模拟服务的解析器及其详情接口
+type platformCase struct {
+	platform videosdk.Platform
+	path     string
+	url      string
+	newFunc  func(baseURL string) videosdk.Parser
+}
+
+var platformCases = []platformCase{
+	{videosdk.PlatformDouyin, parserstest.PathDouyinDetail, "https://www.douyin.com/video/7412345678901234567", parsers.NewDouyinParser},
+	{videosdk.PlatformKuaishou, parserstest.PathKuaishouDetail, "https://www.kuaishou.com/short-video/3xk8fz5m2q9wdqa", parsers.NewKuaishouParser},
+	{videosdk.PlatformXiaohongshu, parserstest.PathXiaohongshuDetail, "https://www.xiaohongshu.com/explore/66e1a2b3000000001e01c2d3", parsers.NewXiaohongshuParser},
+}
+
+func TestParserFaults(t *testing.T) {
+	backend := parserstest.NewServer()
+	defer backend.Close()
+
+	faults := []struct {
+		name    string
+		setup   func(path string)
+		timeout time.Duration
+		want    videosdk.ErrorCode
+	}{
+		{"success", func(string) {}, 0, ""},
+		{"5xx", func(path string) { backend.FailNext(path, 1, http.StatusBadGateway) }, 0, videosdk.ErrCodeBackendUnavailable},
+		{"malformed", func(path string) { backend.Script(path, parserstest.Malformed()) }, 0, videosdk.ErrCodeBackendUnavailable},
+		{"drop", func(path string) { backend.Script(path, parserstest.Drop()) }, 0, videosdk.ErrCodeBackendUnavailable},
+		{"timeout", func(path string) {
+			backend.Script(path, parserstest.Fixture(defaultFixture(path)).WithDelay(time.Second))
+		}, 50 * time.Millisecond, videosdk.ErrCodeTimeout},
+	}
+
+	for _, pc := range platformCases {
+		for _, fault := range faults {
+			t.Run(string(pc.platform)+"/"+fault.name, func(t *testing.T) {
+				backend.Reset()
+				fault.setup(pc.path)
+
+				ctx := context.Background()
+				if fault.timeout > 0 {
+					var cancel context.CancelFunc
+					ctx, cancel = context.WithTimeout(ctx, fault.timeout)
+					defer cancel()
+				}
+
+				parser := pc.newFunc(backend.URL)
+				info, err := parser.ParseVideo(ctx, &videosdk.ParseRequest{Platform: pc.platform, URL: pc.url})
+				if got := videosdk.ErrorCodeOf(err); got != fault.want {
+					t.Fatalf("error code = %q (%v), want %q", got, err, fault.want)
+				}
+				if fault.want == "" && (info == nil || info.ID == "") {
+					t.Fatalf("got %+v, want parsed video", info)
+				}
+			})
+		}
+	}
+}
+
+func TestParserAccountFixtures(t *testing.T) {
+	backend := parserstest.NewServer()
+	defer backend.Close()
+
+	tests := []struct {
+		platform videosdk.Platform
+		fixture  string
+		want     videosdk.ErrorCode
+	}{
+		{videosdk.PlatformDouyin, "douyin_login_required.json", videosdk.ErrCodeLoginRequired},
+		{videosdk.PlatformKuaishou, "kuaishou_verification_required.json", videosdk.ErrCodeVerificationRequired},
+		{videosdk.PlatformXiaohongshu, "xiaohongshu_login_required.json", videosdk.ErrCodeLoginRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			pc := findPlatform(tt.platform)
+			backend.Reset()
+			backend.SetDefault(pc.path, parserstest.Fixture(tt.fixture))
+
+			_, err := pc.newFunc(backend.URL).ParseVideo(context.Background(), &videosdk.ParseRequest{Platform: pc.platform, URL: pc.url})
+			if got := videosdk.ErrorCodeOf(err); got != tt.want {
+				t.Fatalf("error code = %q (%v), want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// defaultFixture 接口默认返回的样例数据
+func defaultFixture(path string) string {
+	switch path {
+	case parserstest.PathDouyinDetail:
+		return "douyin_video.json"
+	case parserstest.PathKuaishouDetail:
+		return "kuaishou_video.json"
+	}
+	return "xiaohongshu_video.json"
+}
+
+// findPlatform 查找平台对应的用例
+func findPlatform(platform videosdk.Platform) platformCase {
+	for _, pc := range platformCases {
+		if pc.platform == platform {
+			return pc
+		}
+	}
+	panic("unknown platform " + platform)
+}
+
+func TestDouyinSourceSingleRequest(t *testing.T) {
+	backend := parserstest.NewServer()
+	defer backend.Close()
+
+	parser := parsers.NewDouyinParser(backend.URL)
+	info, err := parser.ParseVideo(context.Background(), &videosdk.ParseRequest{
+		Platform: videosdk.PlatformDouyin,
+		URL:      "https://www.douyin.com/video/7412345678901234567",
+		Source:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(backend.Requests(parserstest.PathDouyinDetail)); n != 1 {
+		t.Errorf("detail requests = %d, want 1", n)
+	}
+	if id := gjson.GetBytes(info.Raw, "id").String(); id != info.ID {
+		t.Errorf("raw id = %q, want %q", id, info.ID)
+	}
+}
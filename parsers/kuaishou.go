@@ -4,7 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
+	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
@@ -32,16 +33,40 @@ func NewKuaishouParser(baseURL string) videosdk.Parser {
 	}
 }
 
+// BaseURL 获取后端服务地址
+func (p *KuaishouParser) BaseURL() string {
+	return p.baseURL
+}
+
+// SetTransport 设置访问后端服务的HTTP传输层
+func (p *KuaishouParser) SetTransport(transport http.RoundTripper) {
+	p.client.SetTransport(transport)
+}
+
+// HealthCheck 检查后端服务是否可用
+func (p *KuaishouParser) HealthCheck(ctx context.Context) error {
+	return checkBackend(ctx, p.client, p.baseURL)
+}
+
 // GetPlatform 获取平台类型
 func (p *KuaishouParser) GetPlatform() videosdk.Platform {
 	return videosdk.PlatformKuaishou
 }
 
-// ExtractVideoID 从URL提取视频ID（快手不需要提取ID，直接使用URL）
+// kuaishouIDPattern 快手作品ID
+var kuaishouIDPattern = regexp.MustCompile(`^[0-9A-Za-z_-]+$`)
+
+// ExtractVideoID 从URL提取视频ID，支持short-video、fw/photo和photo格式的链接
 func (p *KuaishouParser) ExtractVideoID(url string) (string, error) {
-	// 快手解析器不需要提取视频ID，直接使用完整URL
-	// 这个方法保留是为了兼容接口
-	return url, nil
+	return extractCanonicalID(videosdk.PlatformKuaishou, url)
+}
+
+// kuaishouVideoURL 根据作品ID构造作品链接，VideoID本身是链接时原样返回
+func kuaishouVideoURL(videoID string) string {
+	if strings.Contains(videoID, "://") {
+		return videoID
+	}
+	return "https://www.kuaishou.com/short-video/" + videoID
 }
 
 // ValidateRequest 验证请求参数
@@ -54,6 +79,10 @@ func (p *KuaishouParser) ValidateRequest(req *videosdk.ParseRequest) error {
 		return fmt.Errorf("平台类型不匹配，期望: %s，实际: %s", videosdk.PlatformKuaishou, req.Platform)
 	}
 
+	if req.URL == "" && !strings.Contains(req.VideoID, "://") && !kuaishouIDPattern.MatchString(req.VideoID) {
+		return fmt.Errorf("无效的快手作品ID: %s", req.VideoID)
+	}
+
 	return nil
 }
 
@@ -66,10 +95,14 @@ func (p *KuaishouParser) ParseVideo(ctx context.Context, req *videosdk.ParseRequ
 	// 确定要解析的URL
 	var targetURL string
 	if req.URL != "" {
-		targetURL = req.URL
+		resolved, err := videosdk.ResolveShortLink(ctx, req.URL, req.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("解析短链接失败: %w", err)
+		}
+		targetURL = resolved
 	} else if req.VideoID != "" {
-		// 如果只提供了VideoID，假设它是一个短链接或完整URL
-		targetURL = req.VideoID
+		// 只提供作品ID时构造作品链接
+		targetURL = kuaishouVideoURL(req.VideoID)
 	} else {
 		return nil, fmt.Errorf("必须提供URL或VideoID")
 	}
@@ -82,38 +115,55 @@ func (p *KuaishouParser) ParseVideo(ctx context.Context, req *videosdk.ParseRequ
 	}
 
 	// 发送请求到快手API的 /detail/ 接口
-	resp, err := p.client.R().
-		SetContext(ctx).
-		SetBody(requestBody).
-		Post(p.baseURL + "/detail/")
+	resp, err := postBackend(ctx, videosdk.PlatformKuaishou, p.client, p.baseURL, "/detail/", requestBody)
 
 	if err != nil {
-		return nil, fmt.Errorf("请求快手API失败: %w", err)
+		return nil, backendError(fmt.Errorf("请求快手API失败: %w", err))
 	}
 
 	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("快手API请求失败，状态码: %d", resp.StatusCode())
+		return nil, statusError(resp.StatusCode(), "快手API请求失败")
 	}
 
 	// 解析响应
-	videoInfo, err := p.parseVideoData(resp.Body(), targetURL)
+	result, err := decodeResponse(resp)
 	if err != nil {
+		logRejectedResponse(ctx, videosdk.PlatformKuaishou, resp, err)
+		return nil, err
+	}
+
+	videoInfo, err := p.parseVideoData(ctx, result, targetURL)
+	if err != nil {
+		logRejectedResponse(ctx, videosdk.PlatformKuaishou, resp, err)
 		return nil, fmt.Errorf("解析响应失败: %w", err)
 	}
 
+	// 快手后端不提供平台原始数据，返回后端的作品数据
+	if req.Source {
+		videoInfo.Raw = rawData(result)
+	}
+
 	return videoInfo, nil
 }
 
 // parseVideoData 解析快手API返回的视频数据
-func (p *KuaishouParser) parseVideoData(data []byte, url string) (*videosdk.VideoInfo, error) {
-	result := gjson.ParseBytes(data)
-
+func (p *KuaishouParser) parseVideoData(ctx context.Context, result gjson.Result, url string) (*videosdk.VideoInfo, error) {
+	// 获取失败时后端返回"data": null或缺少下载地址，优先按消息识别Cookie失效和验证
 	videoData := result.Get("data")
-	if !videoData.Exists() {
-		return nil, errors.New("响应中缺少data字段")
+	downloadUrl := videoData.Get("download")
+	if !videoData.IsObject() || !downloadUrl.Exists() {
+		if err := messageError(result.Get("message").String()); err != nil {
+			return nil, err
+		}
+	}
+	if !videoData.IsObject() {
+		return nil, fmt.Errorf("响应中没有作品数据: %s", result.Get("message").String())
+	}
+
+	if err := videosdk.CheckSchema(ctx, kuaishouDetailSchema, result); err != nil {
+		return nil, err
 	}
 
-	downloadUrl := videoData.Get("download")
 	if !downloadUrl.Exists() {
 		return nil, errors.New("解析失败")
 	}
@@ -122,32 +172,24 @@ func (p *KuaishouParser) parseVideoData(data []byte, url string) (*videosdk.Vide
 	videoID := videoData.Get("detailID").String()
 	caption := videoData.Get("caption").String()
 	photoType := videoData.Get("photoType").String()
-	duration := videoData.Get("duration").String()
+	duration := videosdk.ParseDuration(videoData.Get("duration").String())
 	coverURL := videoData.Get("coverUrl").String()
 	downloadURL := downloadUrl.String()
 	timestamp := videoData.Get("timestamp").String()
 
 	// 解析统计数据
-	realLikeCount := videoData.Get("realLikeCount").Int()
-	viewCount := videoData.Get("viewCount")
-	shareCount := videoData.Get("shareCount").Int()
-	commentCount := videoData.Get("commentCount").Int()
+	likeCount := parseCount(videoData.Get("realLikeCount"))
+	playCount := parseCount(videoData.Get("viewCount"))
+	shareCount := parseCount(videoData.Get("shareCount"))
+	commentCount := parseCount(videoData.Get("commentCount"))
+	collectCount := parseCount(videoData.Get("collectCount"))
 
 	// 解析作者信息
 	authorID := videoData.Get("authorID").String()
 	authorName := videoData.Get("name").String()
 
-	// 解析创建时间
-	var createTime time.Time
-	if timestamp != "" {
-		if parsedTime, err := time.Parse("2006-01-02_15:04:05", timestamp); err == nil {
-			createTime = parsedTime
-		} else {
-			createTime = time.Now()
-		}
-	} else {
-		createTime = time.Now()
-	}
+	// 解析创建时间（无法解析时保持零值）
+	createTime := videosdk.ParseTime(timestamp)
 
 	// 确定视频类型
 	var videoType videosdk.VideoType
@@ -160,25 +202,6 @@ func (p *KuaishouParser) parseVideoData(data []byte, url string) (*videosdk.Vide
 		videoType = videosdk.VideoTypeUnknown
 	}
 
-	// 解析播放量（可能是字符串格式，如"203.7万"）
-	var playCount int64
-	if viewCount.Exists() {
-		if viewCount.Type == gjson.Number {
-			playCount = viewCount.Int()
-		} else {
-			// 处理"203.7万"这样的格式
-			viewStr := viewCount.String()
-			if strings.Contains(viewStr, "万") {
-				numStr := strings.Replace(viewStr, "万", "", -1)
-				if num, err := strconv.ParseFloat(numStr, 64); err == nil {
-					playCount = int64(num * 10000)
-				}
-			} else if num, err := strconv.ParseInt(viewStr, 10, 64); err == nil {
-				playCount = num
-			}
-		}
-	}
-
 	// 处理下载链接
 	var downloadURLs []string
 	if downloadURL != "" {
@@ -219,15 +242,16 @@ func (p *KuaishouParser) parseVideoData(data []byte, url string) (*videosdk.Vide
 		Width:       0,
 		Height:      0,
 		Author: videosdk.AuthorInfo{
-			UID:      authorID,
-			Nickname: authorName,
+			UID:           authorID,
+			Nickname:      authorName,
+			FollowerCount: parseCount(videoData.Get("fansCount")),
 		},
 		Stats: videosdk.VideoStats{
 			PlayCount:    playCount,
-			LikeCount:    realLikeCount,
+			LikeCount:    likeCount,
 			CommentCount: commentCount,
 			ShareCount:   shareCount,
-			CollectCount: 0,
+			CollectCount: collectCount,
 		},
 		Music: videosdk.MusicInfo{},
 		Tags:  []string{},
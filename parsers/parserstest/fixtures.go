@@ -0,0 +1,58 @@
+package parserstest
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+// fixtures 内置的样例数据，格式与真实下载服务的响应一致
+//
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Fixtures 获取全部内置样例数据的名称
+//
+//	douyin_share.json                    抖音分享链接解析结果
+//	douyin_video.json                    抖音视频作品
+//	douyin_images.json                   抖音图集作品
+//	douyin_not_found.json                抖音作品不存在
+//	douyin_login_required.json           抖音Cookie失效
+//	kuaishou_video.json                  快手视频作品
+//	kuaishou_images.json                 快手图集作品
+//	kuaishou_verification_required.json  快手触发滑块验证
+//	xiaohongshu_video.json               小红书视频笔记
+//	xiaohongshu_images.json              小红书图文笔记（含动图）
+//	xiaohongshu_login_required.json      小红书Cookie失效
+func Fixtures() []string {
+	entries, err := fs.ReadDir(fixtures, "fixtures")
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadFixture 读取内置样例数据
+func LoadFixture(name string) ([]byte, error) {
+	data, err := fixtures.ReadFile("fixtures/" + name)
+	if err != nil {
+		return nil, fmt.Errorf("parserstest: fixture %q not found", name)
+	}
+	return data, nil
+}
+
+// MustFixture 读取内置样例数据，不存在时panic
+func MustFixture(name string) []byte {
+	data, err := LoadFixture(name)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -0,0 +1,153 @@
+package parserstest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/parsers"
+)
+
+// GoldenCase 黄金文件用例：用样例数据驱动解析器，断言完整的VideoInfo
+type GoldenCase struct {
+	Name    string                 // 用例名，对应黄金文件名<Name>.golden.json
+	Path    string                 // 返回样例数据的接口
+	Fixture string                 // 样例数据
+	Request *videosdk.ParseRequest // 解析请求
+}
+
+// GoldenCases 内置样例数据对应的黄金文件用例，覆盖三个解析器的parseVideoData
+var GoldenCases = []GoldenCase{
+	{
+		Name:    "douyin_video",
+		Path:    PathDouyinDetail,
+		Fixture: "douyin_video.json",
+		Request: &videosdk.ParseRequest{Platform: videosdk.PlatformDouyin, URL: "https://v.douyin.com/iRNBho6u/"},
+	},
+	{
+		Name:    "douyin_images",
+		Path:    PathDouyinDetail,
+		Fixture: "douyin_images.json",
+		Request: &videosdk.ParseRequest{Platform: videosdk.PlatformDouyin, URL: "https://www.douyin.com/note/7398765432109876543"},
+	},
+	{
+		Name:    "kuaishou_video",
+		Path:    PathKuaishouDetail,
+		Fixture: "kuaishou_video.json",
+		Request: &videosdk.ParseRequest{Platform: videosdk.PlatformKuaishou, URL: "https://www.kuaishou.com/short-video/3xk8fz5m2q9wdqa"},
+	},
+	{
+		Name:    "kuaishou_images",
+		Path:    PathKuaishouDetail,
+		Fixture: "kuaishou_images.json",
+		Request: &videosdk.ParseRequest{Platform: videosdk.PlatformKuaishou, URL: "https://www.kuaishou.com/short-video/3x2p9a7kd5mhs6e"},
+	},
+	{
+		Name:    "xiaohongshu_video",
+		Path:    PathXiaohongshuDetail,
+		Fixture: "xiaohongshu_video.json",
+		Request: &videosdk.ParseRequest{Platform: videosdk.PlatformXiaohongshu, URL: "https://www.xiaohongshu.com/explore/66e1a2b3000000001e01c2d3"},
+	},
+	{
+		Name:    "xiaohongshu_images",
+		Path:    PathXiaohongshuDetail,
+		Fixture: "xiaohongshu_images.json",
+		Request: &videosdk.ParseRequest{Platform: videosdk.PlatformXiaohongshu, URL: "https://www.xiaohongshu.com/explore/66d0c1b2000000001f03a4b5"},
+	},
+}
+
+// newParser 创建指向模拟服务的解析器
+func newParser(platform videosdk.Platform, baseURL string) videosdk.Parser {
+	switch platform {
+	case videosdk.PlatformDouyin:
+		return parsers.NewDouyinParser(baseURL)
+	case videosdk.PlatformKuaishou:
+		return parsers.NewKuaishouParser(baseURL)
+	case videosdk.PlatformXiaohongshu:
+		return parsers.NewXiaohongshuParser(baseURL)
+	}
+	return nil
+}
+
+// VerifyGoldens 运行全部黄金文件用例，将解析结果与dir下的黄金文件比较
+//
+// update为true时用当前结果覆盖黄金文件。可在测试中调用：
+//
+//	func TestGoldens(t *testing.T) {
+//		if err := parserstest.VerifyGoldens("testdata/golden", os.Getenv("UPDATE_GOLDEN") != ""); err != nil {
+//			t.Fatal(err)
+//		}
+//	}
+func VerifyGoldens(dir string, update bool) error {
+	backend := NewServer()
+	defer backend.Close()
+
+	var errs []error
+	for _, c := range GoldenCases {
+		backend.Reset()
+		backend.SetDefault(c.Path, Fixture(c.Fixture))
+
+		parser := newParser(c.Request.Platform, backend.URL)
+		info, err := parser.ParseVideo(context.Background(), c.Request)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Name, err))
+			continue
+		}
+		if err := CompareGolden(filepath.Join(dir, c.Name+".golden.json"), info, update); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CompareGolden 将got的JSON编码与黄金文件比较，update为true时覆盖黄金文件
+func CompareGolden(path string, got interface{}, update bool) error {
+	actual, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal result: %w", err)
+	}
+	actual = append(actual, '\n')
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, actual, 0o644)
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read golden file: %w", err)
+	}
+	if bytes.Equal(expected, actual) {
+		return nil
+	}
+	return fmt.Errorf("result differs from %s:\n%s", path, diffLines(string(expected), string(actual)))
+}
+
+// diffLines 列出两段文本中不同的行
+func diffLines(expected, actual string) string {
+	want := strings.Split(expected, "\n")
+	got := strings.Split(actual, "\n")
+
+	var b strings.Builder
+	for i := 0; i < len(want) || i < len(got); i++ {
+		var w, g string
+		if i < len(want) {
+			w = want[i]
+		}
+		if i < len(got) {
+			g = got[i]
+		}
+		if w != g {
+			fmt.Fprintf(&b, "line %d:\n  - %s\n  + %s\n", i+1, w, g)
+		}
+	}
+	return b.String()
+}
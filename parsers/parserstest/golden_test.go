@@ -0,0 +1,97 @@
+package parserstest
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// update 为true时重新生成黄金文件和磁带：go test ./parsers/parserstest -update，或设置UPDATE_GOLDEN=1
+var update = flag.Bool("update", false, "rewrite golden files and cassettes")
+
+// updating 是否重新生成黄金文件和磁带
+func updating() bool {
+	return *update || os.Getenv("UPDATE_GOLDEN") != ""
+}
+
+func TestGoldens(t *testing.T) {
+	if err := VerifyGoldens("golden", updating()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// replayBaseURL 磁带中记录的后端地址，回放时不会真正访问
+const replayBaseURL = "http://backend.test"
+
+func TestReplayCassette(t *testing.T) {
+	cassette := filepath.Join("testdata", "douyin_video.cassette.json")
+	if updating() {
+		recordCassette(t, cassette)
+	}
+
+	recorder, err := NewRecorder(cassette, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := parseDouyinVideo(recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 磁带中的响应经过脱敏，结果与golden下未脱敏的黄金文件不同，单独保存
+	if err := CompareGolden(filepath.Join("testdata", "douyin_video.golden.json"), info, updating()); err != nil {
+		t.Fatal(err)
+	}
+	if unused := recorder.Unused(); len(unused) > 0 {
+		t.Fatalf("unused cassette requests: %+v", unused)
+	}
+}
+
+// recordCassette 对模拟服务录制磁带，磁带中保留固定的后端地址
+func recordCassette(t *testing.T, path string) {
+	t.Helper()
+
+	backend := NewServer()
+	defer backend.Close()
+
+	recorder, err := NewRecorder(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, _ := url.Parse(backend.URL)
+	recorder.SetTransport(rewriteHost{host: target.Host})
+
+	if _, err := parseDouyinVideo(recorder); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// parseDouyinVideo 通过录制回放器解析抖音短链接，依次请求分享链接解析和作品详情接口
+func parseDouyinVideo(recorder *Recorder) (*videosdk.VideoInfo, error) {
+	parser := newParser(videosdk.PlatformDouyin, replayBaseURL)
+	parser.(videosdk.TransportSetter).SetTransport(recorder)
+	return parser.ParseVideo(context.Background(), &videosdk.ParseRequest{
+		Platform: videosdk.PlatformDouyin,
+		URL:      "https://v.douyin.com/iRNBho6u/",
+	})
+}
+
+// rewriteHost 将请求转发到指定主机的传输层
+type rewriteHost struct {
+	host string
+}
+
+func (t rewriteHost) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Host = t.host
+	return http.DefaultTransport.RoundTrip(req)
+}
@@ -0,0 +1,358 @@
+package parserstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// Mode 录制回放模式
+type Mode string
+
+const (
+	ModeRecord Mode = "record" // 请求真实服务并录制，覆盖已有磁带
+	ModeReplay Mode = "replay" // 只从磁带回放，未匹配的请求返回错误
+	ModeAuto   Mode = "auto"   // 磁带存在时回放，否则录制
+)
+
+// scrubbed 脱敏后的占位值
+const scrubbed = "[SCRUBBED]"
+
+// defaultScrubHeaders 默认脱敏的请求头和响应头
+var defaultScrubHeaders = []string{"Cookie", "Set-Cookie", "Authorization", "Proxy-Authorization", "X-Api-Key"}
+
+// RecordedRequest 磁带中的请求
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse 磁带中的响应
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// Interaction 一次请求和响应
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// Cassette 磁带文件，保存按顺序录制的请求和响应
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Matcher 判断磁带中的请求与实际请求是否匹配
+type Matcher func(recorded, actual RecordedRequest) bool
+
+// DefaultMatcher 按请求方法、URL和请求体（JSON按语义比较）匹配
+func DefaultMatcher(recorded, actual RecordedRequest) bool {
+	return recorded.Method == actual.Method &&
+		recorded.URL == actual.URL &&
+		equalBody(recorded.Body, actual.Body)
+}
+
+// Recorder 录制回放HTTP请求的RoundTripper
+//
+// 录制时请求和响应中的Cookie、Authorization等请求头，以及请求体和响应体中的Cookie、
+// Token和代理密码会被脱敏后写入磁带，回放时实际请求经过同样的脱敏再与磁带匹配。
+// 通过解析器的SetTransport注入：
+//
+//	recorder, _ := parserstest.NewRecorder("testdata/douyin.json", parserstest.ModeAuto)
+//	defer recorder.Save()
+//	parser.(videosdk.TransportSetter).SetTransport(recorder)
+type Recorder struct {
+	mu           sync.Mutex
+	path         string
+	mode         Mode
+	transport    http.RoundTripper
+	scrubHeaders []string
+	matcher      Matcher
+	cassette     Cassette
+	used         []bool
+	dirty        bool
+}
+
+// NewRecorder 创建录制回放器，回放模式下磁带不存在时返回错误
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		path:         path,
+		mode:         mode,
+		transport:    http.DefaultTransport,
+		scrubHeaders: defaultScrubHeaders,
+		matcher:      DefaultMatcher,
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if mode == ModeAuto {
+			r.mode = ModeReplay
+		}
+	case errors.Is(err, os.ErrNotExist) && mode != ModeReplay:
+		if mode == ModeAuto {
+			r.mode = ModeRecord
+		}
+		return r, nil
+	default:
+		return nil, fmt.Errorf("parserstest: read cassette: %w", err)
+	}
+
+	if r.mode == ModeRecord {
+		return r, nil
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("parserstest: parse cassette %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Mode 实际使用的模式（ModeAuto会被解析为录制或回放）
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// SetTransport 设置录制时访问真实服务的传输层，默认http.DefaultTransport
+func (r *Recorder) SetTransport(transport http.RoundTripper) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transport = transport
+}
+
+// ScrubHeaders 追加需要脱敏的请求头和响应头
+func (r *Recorder) ScrubHeaders(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scrubHeaders = append(r.scrubHeaders[:len(r.scrubHeaders):len(r.scrubHeaders)], names...)
+}
+
+// SetMatcher 设置请求匹配规则
+func (r *Recorder) SetMatcher(matcher Matcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.matcher = matcher
+}
+
+// RoundTrip 实现http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	actual := RecordedRequest{
+		Method: req.Method,
+		URL:    videosdk.RedactURL(req.URL.String()),
+		Header: r.scrub(req.Header),
+		Body:   scrubBody(body),
+	}
+	mode := r.mode
+	transport := r.transport
+	r.mu.Unlock()
+
+	if mode == ModeReplay {
+		return r.replay(req, actual)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: actual,
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+			Header: r.scrub(resp.Header),
+			Body:   scrubBody(respBody),
+		},
+	})
+	r.used = append(r.used, true)
+	r.dirty = true
+	return resp, nil
+}
+
+// Save 将录制的磁带写入文件，回放模式或没有新录制时不做任何操作
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	r.dirty = false
+	return nil
+}
+
+// Unused 获取磁带中尚未被回放的请求，可用于检查解析器是否少发了请求
+func (r *Recorder) Unused() []RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []RecordedRequest
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction.Request)
+		}
+	}
+	return unused
+}
+
+// replay 按顺序查找第一个未使用的匹配请求，全部使用过时复用最后一个匹配的请求
+func (r *Recorder) replay(req *http.Request, actual RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !r.matcher(interaction.Request, actual) {
+			continue
+		}
+		found = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("parserstest: no recorded interaction for %s %s in %s", actual.Method, actual.URL, r.path)
+	}
+	r.used[found] = true
+
+	recorded := r.cassette.Interactions[found].Response
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// scrub 复制请求头或响应头并脱敏（调用方需持有锁）
+func (r *Recorder) scrub(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	scrubbedHeader := header.Clone()
+	for _, name := range r.scrubHeaders {
+		if _, ok := scrubbedHeader[http.CanonicalHeaderKey(name)]; ok {
+			scrubbedHeader.Set(name, scrubbed)
+		}
+	}
+	return scrubbedHeader
+}
+
+// scrubBody 脱敏请求体或响应体
+//
+// JSON按结构处理：敏感字段的非空字符串值替换为占位值，其余字符串隐去URL凭据，
+// 保证脱敏后仍是合法JSON；非JSON按文本脱敏。
+func scrubBody(body []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil || decoder.More() {
+		return videosdk.RedactText(string(body))
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(scrubValue("", v)); err != nil {
+		return videosdk.RedactText(string(body))
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// scrubValue 递归脱敏JSON值，key为所在字段名
+func scrubValue(key string, v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = scrubValue(k, item)
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = scrubValue(key, item)
+		}
+		return value
+	case string:
+		if value != "" && videosdk.IsSensitiveKey(key) {
+			return scrubbed
+		}
+		return videosdk.RedactText(value)
+	default:
+		return v
+	}
+}
+
+// readRequestBody 读取请求体并恢复，使请求仍可被发送
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// equalBody 比较请求体，两者都是JSON时按语义比较
+func equalBody(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var va, vb interface{}
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return bytes.Equal(ja, jb)
+}
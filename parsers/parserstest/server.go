@@ -0,0 +1,291 @@
+// Package parserstest 提供模拟后端服务，用于在不启动真实下载服务的情况下测试解析器
+//
+// Server基于httptest实现了/douyin/share、/douyin/detail、/detail/和/xhs/detail接口，
+// 默认返回内置的样例数据，也可以按接口编排响应序列并注入延迟、5xx和格式错误的JSON，
+// 或用Redirect编排跳转响应来模拟短链接：
+//
+//	backend := parserstest.NewServer()
+//	defer backend.Close()
+//
+//	backend.Script(parserstest.PathDouyinDetail,
+//		parserstest.Error(http.StatusBadGateway),
+//		parserstest.Fixture("douyin_video.json").WithDelay(200*time.Millisecond),
+//	)
+//	parser := parsers.NewDouyinParser(backend.URL)
+package parserstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// 模拟的后端接口
+const (
+	PathDouyinShare       = "/douyin/share"  // 抖音分享链接解析
+	PathDouyinDetail      = "/douyin/detail" // 抖音作品详情
+	PathKuaishouDetail    = "/detail/"       // 快手作品详情
+	PathXiaohongshuDetail = "/xhs/detail"    // 小红书作品详情
+)
+
+// defaultFixtures 各接口默认返回的样例数据
+var defaultFixtures = map[string]string{
+	PathDouyinShare:       "douyin_share.json",
+	PathDouyinDetail:      "douyin_video.json",
+	PathKuaishouDetail:    "kuaishou_video.json",
+	PathXiaohongshuDetail: "xiaohongshu_video.json",
+}
+
+// Response 模拟接口的一次响应
+type Response struct {
+	Status    int           // HTTP状态码，默认200
+	Body      []byte        // 响应体
+	Header    http.Header   // 额外的响应头
+	Delay     time.Duration // 返回前的延迟
+	Malformed bool          // 截断响应体，模拟格式错误的JSON
+	Drop      bool          // 不返回响应直接断开连接，模拟网络故障
+}
+
+// JSON 创建以v的JSON编码为响应体的响应
+func JSON(v interface{}) Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("parserstest: marshal response: %v", err))
+	}
+	return Response{Status: http.StatusOK, Body: body}
+}
+
+// Fixture 创建以内置样例数据为响应体的响应，样例不存在时panic
+func Fixture(name string) Response {
+	return Response{Status: http.StatusOK, Body: MustFixture(name)}
+}
+
+// Error 创建指定状态码的错误响应
+func Error(status int) Response {
+	return Response{Status: status, Body: []byte(fmt.Sprintf(`{"detail":"%s"}`, http.StatusText(status)))}
+}
+
+// Malformed 创建响应体为格式错误JSON的响应
+func Malformed() Response {
+	return Response{Status: http.StatusOK, Body: MustFixture("douyin_video.json"), Malformed: true}
+}
+
+// Redirect 创建跳转到location的302响应，可配合ShortLinkResolver.SetEndpoint模拟短链接
+func Redirect(location string) Response {
+	return Response{Status: http.StatusFound, Header: http.Header{"Location": []string{location}}}
+}
+
+// Drop 创建直接断开连接的响应
+func Drop() Response {
+	return Response{Drop: true}
+}
+
+// WithDelay 返回增加了延迟的响应副本
+func (r Response) WithDelay(delay time.Duration) Response {
+	r.Delay = delay
+	return r
+}
+
+// WithStatus 返回修改了状态码的响应副本
+func (r Response) WithStatus(status int) Response {
+	r.Status = status
+	return r
+}
+
+// WithHeader 返回增加了响应头的响应副本
+func (r Response) WithHeader(key, value string) Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Add(key, value)
+	r.Header = header
+	return r
+}
+
+// Request 模拟服务收到的请求
+type Request struct {
+	Method string      // 请求方法
+	Host   string      // 请求的Host，模拟短链接时为短链接域名
+	Path   string      // 请求路径
+	Header http.Header // 请求头
+	Body   []byte      // 请求体
+	Time   time.Time   // 收到请求的时间
+}
+
+// Field 按gjson路径读取请求体中的字段，如Field("detail_id")
+func (r Request) Field(path string) string {
+	return gjson.GetBytes(r.Body, path).String()
+}
+
+// Server 模拟的后端服务
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	defaults map[string]Response
+	scripts  map[string][]Response
+	latency  time.Duration
+	requests []Request
+}
+
+// NewServer 启动模拟后端服务，各接口默认返回内置样例数据
+func NewServer() *Server {
+	s := &Server{
+		defaults: make(map[string]Response),
+		scripts:  make(map[string][]Response),
+	}
+	for path, name := range defaultFixtures {
+		s.defaults[path] = Fixture(name)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// SetDefault 设置接口在编排的响应用完后返回的响应
+func (s *Server) SetDefault(path string, response Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.defaults[path] = response
+}
+
+// Script 为接口追加按顺序返回的响应，每个请求消耗一个，用完后返回默认响应
+func (s *Server) Script(path string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[path] = append(s.scripts[path], responses...)
+}
+
+// FailNext 让接口接下来的n个请求返回指定的错误状态码
+func (s *Server) FailNext(path string, n int, status int) {
+	responses := make([]Response, n)
+	for i := range responses {
+		responses[i] = Error(status)
+	}
+	s.Script(path, responses...)
+}
+
+// SetLatency 为全部接口设置额外的固定延迟
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// Requests 获取收到的请求，path为空时返回全部接口的请求
+func (s *Server) Requests(path string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var requests []Request
+	for _, req := range s.requests {
+		if path == "" || req.Path == path {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+// Reset 清空编排的响应、延迟和请求记录，恢复默认样例数据
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.defaults = make(map[string]Response)
+	for path, name := range defaultFixtures {
+		s.defaults[path] = Fixture(name)
+	}
+	s.scripts = make(map[string][]Response)
+	s.latency = 0
+	s.requests = nil
+}
+
+// handle 处理请求
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	// 根路径用于解析器的健康检查
+	if r.URL.Path == "/" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	response, ok := s.next(Request{
+		Method: r.Method,
+		Host:   r.Host,
+		Path:   r.URL.Path,
+		Header: r.Header.Clone(),
+		Body:   body,
+		Time:   time.Now(),
+	})
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if response.Delay > 0 {
+		select {
+		case <-time.After(response.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if response.Drop {
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	for key, values := range response.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	status := response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+
+	payload := response.Body
+	if response.Malformed {
+		payload = bytes.TrimSpace(payload)
+		payload = payload[:len(payload)/2]
+	}
+	_, _ = w.Write(payload)
+}
+
+// next 记录请求并取出接口的下一个响应，未知接口返回false
+func (s *Server) next(req Request) (Response, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, req)
+
+	var response Response
+	if scripted := s.scripts[req.Path]; len(scripted) > 0 {
+		response = scripted[0]
+		s.scripts[req.Path] = scripted[1:]
+	} else if d, ok := s.defaults[req.Path]; ok {
+		response = d
+	} else {
+		return Response{}, false
+	}
+
+	response.Delay += s.latency
+	return response, true
+}
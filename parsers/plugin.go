@@ -0,0 +1,509 @@
+package parsers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/go-resty/resty/v2"
+)
+
+// PluginProtocolVersion 插件协议版本
+//
+// 插件通过标准输入输出（每行一个JSON消息）或本地HTTP（POST请求体和响应体各一个JSON消息）通信。
+// 请求为{"id": 1, "method": "parse_video", "params": {...}}，
+// 响应为{"id": 1, "result": {...}}或{"id": 1, "error": {"code": "login_required", "message": "..."}}，
+// 标准输入输出模式下响应可以乱序返回，按id对应。方法：
+//
+//	hello             {"protocol": 1}                     -> {"platform": "bilibili", "protocol": 1}
+//	extract_video_id  {"url": "..."}                      -> {"video_id": "..."}
+//	validate_request  ParseRequest                        -> {}
+//	parse_video       {"request": ParseRequest, "timeout_ms": 29000} -> VideoInfo
+//	cancel            {"id": 1}                           （通知，无需响应）
+//
+// error.code使用SDK的错误类别（如login_required、rate_limited），未知类别按parse_failed处理；
+// VideoInfo中缺失或为null的统计数量视为未知。
+const PluginProtocolVersion = 1
+
+// 插件方法
+const (
+	pluginMethodHello          = "hello"
+	pluginMethodExtractVideoID = "extract_video_id"
+	pluginMethodValidate       = "validate_request"
+	pluginMethodParseVideo     = "parse_video"
+	pluginMethodCancel         = "cancel"
+)
+
+// pluginMaxMessage 标准输入输出模式下单条消息的最大字节数
+const pluginMaxMessage = 16 << 20
+
+// PluginConfig 外部插件配置，Command和URL二选一
+type PluginConfig struct {
+	Command []string      // 插件进程的命令及参数，如["python3", "bilibili.py"]，以标准输入输出通信
+	Dir     string        // 插件进程的工作目录
+	Env     []string      // 插件进程额外的环境变量，如"KEY=value"
+	Stderr  io.Writer     // 插件进程的标准错误输出，默认os.Stderr
+	URL     string        // 本地HTTP插件的地址，如"http://127.0.0.1:9000/rpc"
+	Timeout time.Duration // 握手、ExtractVideoID和ValidateRequest的超时时间，默认10s；ParseVideo由上下文控制
+}
+
+// pluginRequest 插件请求
+type pluginRequest struct {
+	ID     int64       `json:"id,omitempty"`
+	Method string      `json:"method"`
+	Params interface{} `json:"params,omitempty"`
+}
+
+// pluginResponse 插件响应
+type pluginResponse struct {
+	ID     int64           `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *pluginError    `json:"error,omitempty"`
+}
+
+// pluginError 插件返回的错误
+type pluginError struct {
+	Code    videosdk.ErrorCode `json:"code"`
+	Message string             `json:"message"`
+}
+
+// err 转换为SDK错误，保留插件给出的错误类别
+func (e *pluginError) err() error {
+	err := errors.New(e.Message)
+	if e.Code == "" {
+		return err
+	}
+	return videosdk.NewError(e.Code, err)
+}
+
+// pluginTransport 插件通信方式
+type pluginTransport interface {
+	call(ctx context.Context, method string, params interface{}) (json.RawMessage, error)
+	close() error
+}
+
+// PluginParser 由外部插件进程实现的解析器
+//
+// 可以用任意语言编写解析器，以标准输入输出或本地HTTP按PluginProtocolVersion描述的协议通信，
+// 再通过VideoSDK.RegisterParser注册。标准输入输出模式下插件进程退出后会在下次调用时自动重启。
+type PluginParser struct {
+	platform  videosdk.Platform
+	timeout   time.Duration
+	transport pluginTransport
+}
+
+// NewPluginParser 启动或连接外部插件并完成握手，插件不可用或协议版本不匹配时返回错误
+func NewPluginParser(cfg PluginConfig) (*PluginParser, error) {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	var transport pluginTransport
+	switch {
+	case len(cfg.Command) > 0 && cfg.URL != "":
+		return nil, errors.New("插件配置中Command和URL只能指定一个")
+	case len(cfg.Command) > 0:
+		transport = &stdioPluginTransport{config: cfg}
+	case cfg.URL != "":
+		client := resty.New()
+		client.SetHeader("Content-Type", "application/json")
+		transport = &httpPluginTransport{client: client, url: cfg.URL}
+	default:
+		return nil, errors.New("插件配置中必须指定Command或URL")
+	}
+
+	p := &PluginParser{timeout: timeout, transport: transport}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var hello struct {
+		Platform videosdk.Platform `json:"platform"`
+		Protocol int               `json:"protocol"`
+	}
+	if err := p.call(ctx, pluginMethodHello, map[string]int{"protocol": PluginProtocolVersion}, &hello); err != nil {
+		transport.close()
+		return nil, fmt.Errorf("插件握手失败: %w", err)
+	}
+	if hello.Protocol != PluginProtocolVersion {
+		transport.close()
+		return nil, fmt.Errorf("插件协议版本不匹配，期望: %d，实际: %d", PluginProtocolVersion, hello.Protocol)
+	}
+	if hello.Platform == "" {
+		transport.close()
+		return nil, errors.New("插件没有返回平台类型")
+	}
+	p.platform = hello.Platform
+	return p, nil
+}
+
+// Close 关闭插件进程，HTTP插件无需关闭
+func (p *PluginParser) Close() error {
+	return p.transport.close()
+}
+
+// SetTransport 设置访问HTTP插件的传输层，标准输入输出插件忽略
+func (p *PluginParser) SetTransport(transport http.RoundTripper) {
+	if t, ok := p.transport.(*httpPluginTransport); ok {
+		t.client.SetTransport(transport)
+	}
+}
+
+// HealthCheck 重新握手检查插件是否可用
+func (p *PluginParser) HealthCheck(ctx context.Context) error {
+	return p.call(ctx, pluginMethodHello, map[string]int{"protocol": PluginProtocolVersion}, nil)
+}
+
+// GetPlatform 获取平台类型（握手时由插件返回）
+func (p *PluginParser) GetPlatform() videosdk.Platform {
+	return p.platform
+}
+
+// ExtractVideoID 从URL提取视频ID
+func (p *PluginParser) ExtractVideoID(url string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	var result struct {
+		VideoID string `json:"video_id"`
+	}
+	if err := p.call(ctx, pluginMethodExtractVideoID, map[string]string{"url": url}, &result); err != nil {
+		return "", err
+	}
+	return result.VideoID, nil
+}
+
+// ValidateRequest 验证请求参数
+func (p *PluginParser) ValidateRequest(req *videosdk.ParseRequest) error {
+	if req.Platform != p.platform {
+		return fmt.Errorf("平台类型不匹配，期望: %s，实际: %s", p.platform, req.Platform)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	return p.call(ctx, pluginMethodValidate, req, nil)
+}
+
+// ParseVideo 解析视频信息
+func (p *PluginParser) ParseVideo(ctx context.Context, req *videosdk.ParseRequest) (*videosdk.VideoInfo, error) {
+	if req.Platform != p.platform {
+		return nil, fmt.Errorf("平台类型不匹配，期望: %s，实际: %s", p.platform, req.Platform)
+	}
+
+	ctx, span := videosdk.StartSpan(ctx, "PluginParser.ParseVideo", videosdk.Attribute{Key: "platform", Value: string(p.platform)})
+	defer span.End()
+
+	req, err := resolveRequestURL(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	params := map[string]interface{}{"request": req}
+	if deadline, ok := ctx.Deadline(); ok {
+		params["timeout_ms"] = time.Until(deadline).Milliseconds()
+	}
+
+	// 插件未返回的统计数量保持未知
+	info := &videosdk.VideoInfo{
+		Author: videosdk.AuthorInfo{FollowerCount: videosdk.CountUnknown},
+		Stats: videosdk.VideoStats{
+			PlayCount:    videosdk.CountUnknown,
+			LikeCount:    videosdk.CountUnknown,
+			CommentCount: videosdk.CountUnknown,
+			ShareCount:   videosdk.CountUnknown,
+			CollectCount: videosdk.CountUnknown,
+		},
+	}
+	if err := p.call(ctx, pluginMethodParseVideo, params, info); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if info.Platform == "" {
+		info.Platform = p.platform
+	}
+	return info, nil
+}
+
+// call 调用插件方法，result为nil时忽略返回值
+func (p *PluginParser) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	raw, err := p.transport.call(ctx, method, params)
+	if err != nil {
+		return err
+	}
+	if result == nil || len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, result); err != nil {
+		return fmt.Errorf("解析插件%s返回值失败: %w", method, err)
+	}
+	return nil
+}
+
+// httpPluginTransport 本地HTTP插件
+type httpPluginTransport struct {
+	client *resty.Client
+	url    string
+}
+
+// call 以一次POST请求调用插件方法
+func (t *httpPluginTransport) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	resp, err := t.client.R().
+		SetContext(ctx).
+		SetBody(pluginRequest{ID: 1, Method: method, Params: params}).
+		Post(t.url)
+	if err != nil {
+		return nil, backendError(fmt.Errorf("请求插件失败: %w", err))
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, statusError(resp.StatusCode(), "插件请求失败")
+	}
+
+	var response pluginResponse
+	if err := json.Unmarshal(resp.Body(), &response); err != nil {
+		return nil, backendError(fmt.Errorf("插件返回的响应不是合法的JSON: %w", err))
+	}
+	if response.Error != nil {
+		return nil, response.Error.err()
+	}
+	return response.Result, nil
+}
+
+// close HTTP插件无需关闭
+func (t *httpPluginTransport) close() error {
+	return nil
+}
+
+// stdioPluginTransport 以标准输入输出通信的插件进程
+type stdioPluginTransport struct {
+	config PluginConfig
+
+	mu      sync.Mutex
+	process *pluginProcess
+	closed  bool
+}
+
+// pluginProcess 一个运行中的插件进程
+type pluginProcess struct {
+	cmd     *exec.Cmd
+	writeMu sync.Mutex
+	stdin   io.WriteCloser
+	stdout  io.ReadCloser
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan pluginResponse
+	done    chan struct{}
+	err     error
+}
+
+// call 在运行中的插件进程上调用方法
+func (t *stdioPluginTransport) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	process, err := t.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return process.call(ctx, method, params)
+}
+
+// call 发送请求并等待对应id的响应
+func (p *pluginProcess) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	id, ch := p.register()
+	defer p.unregister(id)
+
+	if err := p.send(pluginRequest{ID: id, Method: method, Params: params}); err != nil {
+		return nil, backendError(fmt.Errorf("发送插件请求失败: %w", err))
+	}
+
+	select {
+	case response := <-ch:
+		if response.Error != nil {
+			return nil, response.Error.err()
+		}
+		return response.Result, nil
+	case <-p.done:
+		return nil, backendError(fmt.Errorf("插件进程已退出: %w", p.err))
+	case <-ctx.Done():
+		_ = p.send(pluginRequest{Method: pluginMethodCancel, Params: map[string]int64{"id": id}})
+		return nil, ctx.Err()
+	}
+}
+
+// current 获取运行中的插件进程，进程已退出时重新启动并握手
+func (t *stdioPluginTransport) current(ctx context.Context) (*pluginProcess, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil, backendError(errors.New("插件已关闭"))
+	}
+	if t.process != nil {
+		select {
+		case <-t.process.done:
+		default:
+			return t.process, nil
+		}
+	}
+
+	restart := t.process != nil
+	process, err := startPluginProcess(t.config)
+	if err != nil {
+		return nil, backendError(fmt.Errorf("启动插件进程失败: %w", err))
+	}
+	t.process = process
+
+	// 首次启动由NewPluginParser握手，重启时在这里握手，确保新进程可用；
+	// 握手失败时结束新进程，下次调用重新启动并握手
+	if restart {
+		if _, err := process.call(ctx, pluginMethodHello, map[string]int{"protocol": PluginProtocolVersion}); err != nil {
+			process.kill()
+			return nil, fmt.Errorf("重启插件后握手失败: %w", err)
+		}
+	}
+	return process, nil
+}
+
+// close 关闭标准输入并结束插件进程
+func (t *stdioPluginTransport) close() error {
+	t.mu.Lock()
+	t.closed = true
+	process := t.process
+	t.mu.Unlock()
+
+	if process == nil {
+		return nil
+	}
+	process.stdin.Close()
+	select {
+	case <-process.done:
+	case <-time.After(2 * time.Second):
+		process.kill()
+	}
+	return nil
+}
+
+// kill 结束插件进程并等待readLoop退出
+func (p *pluginProcess) kill() {
+	// 插件的子进程可能仍持有标准输出，关闭读取端让readLoop结束
+	_ = p.cmd.Process.Kill()
+	p.stdout.Close()
+	<-p.done
+}
+
+// startPluginProcess 启动插件进程并开始读取响应
+func startPluginProcess(cfg PluginConfig) (*pluginProcess, error) {
+	cmd := exec.Command(cfg.Command[0], cfg.Command[1:]...)
+	cmd.Dir = cfg.Dir
+	if len(cfg.Env) > 0 {
+		cmd.Env = append(os.Environ(), cfg.Env...)
+	}
+	cmd.Stderr = cfg.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	cmd.WaitDelay = time.Second
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	process := &pluginProcess{
+		cmd:     cmd,
+		stdin:   stdin,
+		stdout:  stdout,
+		pending: make(map[int64]chan pluginResponse),
+		done:    make(chan struct{}),
+	}
+	go process.readLoop(stdout)
+	return process, nil
+}
+
+// register 分配请求id并登记等待响应的通道
+func (p *pluginProcess) register() (int64, chan pluginResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nextID++
+	ch := make(chan pluginResponse, 1)
+	p.pending[p.nextID] = ch
+	return p.nextID, ch
+}
+
+// unregister 取消登记
+func (p *pluginProcess) unregister(id int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.pending, id)
+}
+
+// send 写入一行JSON消息
+func (p *pluginProcess) send(req pluginRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	_, err = p.stdin.Write(append(data, '\n'))
+	return err
+}
+
+// readLoop 逐行读取响应并按id分发，进程退出或输出无法解析时结束
+func (p *pluginProcess) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), pluginMaxMessage)
+
+	var err error
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var response pluginResponse
+		if err = json.Unmarshal(line, &response); err != nil {
+			err = fmt.Errorf("插件输出了无法解析的消息: %s", videosdk.Excerpt(line, excerptLimit))
+			break
+		}
+
+		p.mu.Lock()
+		ch, ok := p.pending[response.ID]
+		p.mu.Unlock()
+		if ok {
+			// 同一id的重复响应直接丢弃
+			select {
+			case ch <- response:
+			default:
+			}
+		}
+	}
+	if err == nil {
+		err = scanner.Err()
+	}
+	if err == nil {
+		err = io.EOF
+	}
+
+	// 输出异常时结束进程，下次调用会重新启动
+	_ = p.cmd.Process.Kill()
+	if waitErr := p.cmd.Wait(); waitErr != nil && errors.Is(err, io.EOF) {
+		err = waitErr
+	}
+	p.err = err
+	close(p.done)
+}
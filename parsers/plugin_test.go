@@ -0,0 +1,208 @@
+package parsers_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/parsers"
+)
+
+// TestPluginHelperProcess 作为测试插件进程运行，只在GO_WANT_PLUGIN_HELPER=1时生效
+//
+// 视频ID控制插件行为：private返回login_required，exit直接退出进程，
+// slow直到收到cancel通知才以同一id返回（应被丢弃）；其它ID返回带进程号的作品。
+// PLUGIN_HELPER_MARKER指向的文件存在时握手失败，握手成功前parse_video一律返回错误。
+func TestPluginHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_PLUGIN_HELPER") != "1" {
+		return
+	}
+
+	var writeMu sync.Mutex
+	reply := func(response map[string]interface{}) {
+		data, _ := json.Marshal(response)
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		os.Stdout.Write(append(data, '\n'))
+	}
+	fail := func(id int64, code, message string) {
+		reply(map[string]interface{}{"id": id, "error": map[string]string{"code": code, "message": message}})
+	}
+
+	ready := false
+	slow := make(map[int64]bool)
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var request struct {
+			ID     int64           `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			os.Exit(2)
+		}
+
+		switch request.Method {
+		case "hello":
+			if _, err := os.Stat(os.Getenv("PLUGIN_HELPER_MARKER")); err == nil {
+				fail(request.ID, "backend_unavailable", "插件尚未就绪")
+				continue
+			}
+			ready = true
+			reply(map[string]interface{}{"id": request.ID, "result": map[string]interface{}{"platform": "example", "protocol": 1}})
+		case "cancel":
+			var params struct {
+				ID int64 `json:"id"`
+			}
+			json.Unmarshal(request.Params, &params)
+			if slow[params.ID] {
+				delete(slow, params.ID)
+				fail(params.ID, "parse_failed", "已取消的请求不应被接收")
+			}
+		case "parse_video":
+			var params struct {
+				Request videosdk.ParseRequest `json:"request"`
+			}
+			json.Unmarshal(request.Params, &params)
+			switch {
+			case !ready:
+				fail(request.ID, "parse_failed", "握手前收到请求")
+			case params.Request.VideoID == "private":
+				fail(request.ID, "login_required", "作品仅登录后可见")
+			case params.Request.VideoID == "exit":
+				os.Exit(3)
+			case params.Request.VideoID == "slow":
+				slow[request.ID] = true
+			default:
+				reply(map[string]interface{}{"id": request.ID, "result": map[string]interface{}{
+					"id":    params.Request.VideoID,
+					"title": "示例作品",
+					"stats": map[string]interface{}{"like_count": 128, "play_count": nil},
+					"extra": map[string]interface{}{"pid": os.Getpid()},
+				}})
+			}
+		default:
+			fail(request.ID, "invalid_request", "未知方法")
+		}
+	}
+	os.Exit(0)
+}
+
+// startHelperPlugin 以测试二进制自身作为插件进程启动PluginParser
+func startHelperPlugin(t *testing.T, marker string) *parsers.PluginParser {
+	t.Helper()
+	parser, err := parsers.NewPluginParser(parsers.PluginConfig{
+		Command: []string{os.Args[0], "-test.run=^TestPluginHelperProcess$"},
+		Env:     []string{"GO_WANT_PLUGIN_HELPER=1", "PLUGIN_HELPER_MARKER=" + marker},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("启动插件失败: %v", err)
+	}
+	t.Cleanup(func() { parser.Close() })
+	return parser
+}
+
+// parsePlugin 解析指定ID，返回作品和插件进程号
+func parsePlugin(ctx context.Context, parser *parsers.PluginParser, videoID string) (*videosdk.VideoInfo, float64, error) {
+	info, err := parser.ParseVideo(ctx, &videosdk.ParseRequest{Platform: "example", VideoID: videoID})
+	if err != nil {
+		return nil, 0, err
+	}
+	pid, _ := info.Extra["pid"].(float64)
+	return info, pid, nil
+}
+
+func TestPluginParserStdio(t *testing.T) {
+	parser := startHelperPlugin(t, filepath.Join(t.TempDir(), "marker"))
+	if parser.GetPlatform() != "example" {
+		t.Fatalf("握手返回的平台错误: %s", parser.GetPlatform())
+	}
+
+	info, _, err := parsePlugin(context.Background(), parser, "v1")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if info.ID != "v1" || info.Title != "示例作品" || info.Platform != "example" {
+		t.Errorf("作品信息错误: %+v", info)
+	}
+	if info.Stats.LikeCount != 128 {
+		t.Errorf("点赞数错误: %v", info.Stats.LikeCount)
+	}
+	if info.Stats.PlayCount.Known() || info.Stats.CommentCount.Known() {
+		t.Errorf("null或缺失的统计数量应为未知: %+v", info.Stats)
+	}
+
+	_, _, err = parsePlugin(context.Background(), parser, "private")
+	if code := videosdk.ErrorCodeOf(err); code != videosdk.ErrCodeLoginRequired {
+		t.Errorf("插件错误类别应为login_required，实际: %s (%v)", code, err)
+	}
+}
+
+func TestPluginParserCancel(t *testing.T) {
+	parser := startHelperPlugin(t, filepath.Join(t.TempDir(), "marker"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, _, err := parsePlugin(ctx, parser, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("超时后应返回context.DeadlineExceeded，实际: %v", err)
+	}
+
+	// 取消后插件以旧id返回的响应不能被后续请求收到
+	for _, id := range []string{"v1", "v2"} {
+		info, _, err := parsePlugin(context.Background(), parser, id)
+		if err != nil {
+			t.Fatalf("取消后解析%s失败: %v", id, err)
+		}
+		if info.ID != id {
+			t.Errorf("响应与请求不对应，期望: %s，实际: %s", id, info.ID)
+		}
+	}
+}
+
+func TestPluginParserRestart(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "marker")
+	parser := startHelperPlugin(t, marker)
+
+	_, firstPID, err := parsePlugin(context.Background(), parser, "v1")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	_, _, err = parsePlugin(context.Background(), parser, "exit")
+	if code := videosdk.ErrorCodeOf(err); code != videosdk.ErrCodeBackendUnavailable {
+		t.Fatalf("插件进程退出时错误类别应为backend_unavailable，实际: %s (%v)", code, err)
+	}
+
+	// 重启后握手失败，新进程不能被继续使用
+	if err := os.WriteFile(marker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := parsePlugin(context.Background(), parser, "v1"); err == nil || !strings.Contains(err.Error(), "握手失败") {
+		t.Fatalf("重启后握手失败时应返回错误，实际: %v", err)
+	}
+	if err := os.Remove(marker); err != nil {
+		t.Fatal(err)
+	}
+
+	info, secondPID, err := parsePlugin(context.Background(), parser, "v2")
+	if err != nil {
+		t.Fatalf("插件重启后解析失败: %v", err)
+	}
+	if info.ID != "v2" || secondPID == 0 || secondPID == firstPID {
+		t.Errorf("插件应以新进程重启，进程号: %v -> %v", firstPID, secondPID)
+	}
+
+	parser.Close()
+	if _, _, err := parsePlugin(context.Background(), parser, "v3"); videosdk.ErrorCodeOf(err) != videosdk.ErrCodeBackendUnavailable {
+		t.Errorf("关闭后调用应返回backend_unavailable，实际: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package parsers
+
+import (
+	videosdk "github.com/caojianfei/parser"
+)
+
+// 各后端接口成功响应的期望结构，只声明解析器依赖的字段
+
+// douyinShareSchema 抖音分享链接解析接口
+var douyinShareSchema = &videosdk.Schema{
+	Name: "douyin.share",
+	Fields: []videosdk.FieldSpec{
+		{Path: "url", Type: videosdk.FieldString},
+	},
+}
+
+// douyinDetailSchema 抖音作品详情接口
+var douyinDetailSchema = &videosdk.Schema{
+	Name: "douyin.detail",
+	Fields: []videosdk.FieldSpec{
+		{Path: "data.id", Type: videosdk.FieldString},
+		{Path: "data.type", Type: videosdk.FieldString},
+		{Path: "data.desc", Type: videosdk.FieldString},
+		{Path: "data.share_url", Type: videosdk.FieldString},
+		{Path: "data.create_timestamp", Type: videosdk.FieldNumber},
+		{Path: "data.static_cover", Type: videosdk.FieldString},
+		{Path: "data.downloads", Type: videosdk.FieldAny},
+		{Path: "data.uid", Type: videosdk.FieldString},
+		{Path: "data.nickname", Type: videosdk.FieldString},
+		{Path: "data.play_count", Type: videosdk.FieldCount},
+		{Path: "data.digg_count", Type: videosdk.FieldCount, Aliases: []string{"data.like_count"}},
+		{Path: "data.comment_count", Type: videosdk.FieldCount},
+		{Path: "data.share_count", Type: videosdk.FieldCount},
+		{Path: "data.collect_count", Type: videosdk.FieldCount},
+	},
+}
+
+// kuaishouDetailSchema 快手作品详情接口
+var kuaishouDetailSchema = &videosdk.Schema{
+	Name: "kuaishou.detail",
+	Fields: []videosdk.FieldSpec{
+		{Path: "data.detailID", Type: videosdk.FieldString},
+		{Path: "data.photoType", Type: videosdk.FieldString},
+		{Path: "data.caption", Type: videosdk.FieldString},
+		{Path: "data.coverUrl", Type: videosdk.FieldString},
+		{Path: "data.download", Type: videosdk.FieldString},
+		{Path: "data.timestamp", Type: videosdk.FieldString},
+		{Path: "data.authorID", Type: videosdk.FieldString},
+		{Path: "data.name", Type: videosdk.FieldString},
+		{Path: "data.viewCount", Type: videosdk.FieldCount},
+		{Path: "data.realLikeCount", Type: videosdk.FieldCount, Aliases: []string{"data.likeCount"}},
+		{Path: "data.commentCount", Type: videosdk.FieldCount},
+		{Path: "data.shareCount", Type: videosdk.FieldCount},
+	},
+}
+
+// xiaohongshuDetailSchema 小红书作品详情接口
+var xiaohongshuDetailSchema = &videosdk.Schema{
+	Name: "xiaohongshu.detail",
+	Fields: []videosdk.FieldSpec{
+		{Path: "data.作品ID", Type: videosdk.FieldString},
+		{Path: "data.作品类型", Type: videosdk.FieldString},
+		{Path: "data.作品标题", Type: videosdk.FieldString},
+		{Path: "data.作品链接", Type: videosdk.FieldString},
+		{Path: "data.时间戳", Type: videosdk.FieldNumber},
+		{Path: "data.下载地址", Type: videosdk.FieldAny},
+		{Path: "data.作者ID", Type: videosdk.FieldString},
+		{Path: "data.作者昵称", Type: videosdk.FieldString},
+		{Path: "data.点赞数量", Type: videosdk.FieldCount},
+		{Path: "data.收藏数量", Type: videosdk.FieldCount},
+		{Path: "data.评论数量", Type: videosdk.FieldCount},
+		{Path: "data.分享数量", Type: videosdk.FieldCount},
+	},
+}
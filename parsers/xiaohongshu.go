@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
@@ -31,18 +33,43 @@ func NewXiaohongshuParser(baseURL string) videosdk.Parser {
 	}
 }
 
+// BaseURL 获取后端服务地址
+func (p *XiaohongshuParser) BaseURL() string {
+	return p.baseURL
+}
+
+// SetTransport 设置访问后端服务的HTTP传输层
+func (p *XiaohongshuParser) SetTransport(transport http.RoundTripper) {
+	p.client.SetTransport(transport)
+}
+
+// HealthCheck 检查后端服务是否可用
+func (p *XiaohongshuParser) HealthCheck(ctx context.Context) error {
+	return checkBackend(ctx, p.client, p.baseURL)
+}
+
 // GetPlatform 获取平台类型
 func (p *XiaohongshuParser) GetPlatform() videosdk.Platform {
 	return videosdk.PlatformXiaohongshu
 }
 
-// ExtractVideoID 返回视频URL（小红书API直接接受URL参数）
+// xiaohongshuIDPattern 小红书笔记ID（24位十六进制）
+var xiaohongshuIDPattern = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// ExtractVideoID 从URL提取笔记ID，支持explore、discovery/item和用户主页下的笔记链接
 func (p *XiaohongshuParser) ExtractVideoID(url string) (string, error) {
-	// 小红书API直接接受URL参数，无需提取ID
 	if url == "" {
 		return "", fmt.Errorf("URL不能为空")
 	}
-	return url, nil
+	return extractCanonicalID(videosdk.PlatformXiaohongshu, url)
+}
+
+// xiaohongshuNoteURL 根据笔记ID构造笔记链接，VideoID本身是链接时原样返回
+func xiaohongshuNoteURL(videoID string) string {
+	if strings.Contains(videoID, "://") {
+		return videoID
+	}
+	return "https://www.xiaohongshu.com/explore/" + videoID
 }
 
 // ValidateRequest 验证请求参数
@@ -55,6 +82,10 @@ func (p *XiaohongshuParser) ValidateRequest(req *videosdk.ParseRequest) error {
 		return fmt.Errorf("平台类型不匹配，期望: %s，实际: %s", videosdk.PlatformXiaohongshu, req.Platform)
 	}
 
+	if req.URL == "" && !strings.Contains(req.VideoID, "://") && !xiaohongshuIDPattern.MatchString(req.VideoID) {
+		return fmt.Errorf("无效的小红书笔记ID: %s", req.VideoID)
+	}
+
 	return nil
 }
 
@@ -67,13 +98,30 @@ func (p *XiaohongshuParser) ParseVideo(ctx context.Context, req *videosdk.ParseR
 	// 确定要使用的URL
 	url := req.URL
 	if url == "" {
-		url = req.VideoID // VideoID在小红书中实际就是URL
+		url = xiaohongshuNoteURL(req.VideoID)
+	} else {
+		resolved, err := videosdk.ResolveShortLink(ctx, url, req.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("解析短链接失败: %w", err)
+		}
+		url = resolved
 	}
 
 	if url == "" {
 		return nil, fmt.Errorf("URL不能为空")
 	}
 
+	// 请求中的令牌优先于链接中的令牌，缺少令牌的笔记通常无法访问
+	token, source := xsecToken(url)
+	if req.XsecToken != "" {
+		token = req.XsecToken
+	}
+	if req.XsecSource != "" {
+		source = req.XsecSource
+	}
+	url = withXsecToken(url, token, source)
+	noteID, _ := extractCanonicalID(videosdk.PlatformXiaohongshu, url)
+
 	// 构建请求体，严格按照API文档规范
 	requestBody := map[string]interface{}{
 		"url":      url,
@@ -85,41 +133,58 @@ func (p *XiaohongshuParser) ParseVideo(ctx context.Context, req *videosdk.ParseR
 	}
 
 	// 发送POST请求到小红书API的/xhs/接口
-	resp, err := p.client.R().
-		SetContext(ctx).
-		SetBody(requestBody).
-		Post(p.baseURL + "/xhs/detail")
+	resp, err := postBackend(ctx, videosdk.PlatformXiaohongshu, p.client, p.baseURL, "/xhs/detail", requestBody)
 
 	if err != nil {
-		return nil, fmt.Errorf("请求失败: %v", err)
+		return nil, backendError(fmt.Errorf("请求失败: %w", err))
 	}
 
 	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("API请求失败，状态码: %d", resp.StatusCode())
+		return nil, statusError(resp.StatusCode(), "API请求失败")
 	}
 
 	// 解析响应
-	videoInfo, err := p.parseVideoData(resp.Body())
+	result, err := decodeResponse(resp)
+	if err != nil {
+		logRejectedResponse(ctx, videosdk.PlatformXiaohongshu, resp, err)
+		return nil, err
+	}
+
+	videoInfo, err := p.parseVideoData(ctx, result, noteID, token)
 	if err != nil {
-		return nil, fmt.Errorf("解析响应失败: %v", err)
+		logRejectedResponse(ctx, videosdk.PlatformXiaohongshu, resp, err)
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	// 小红书后端不提供平台原始数据，返回后端的作品数据
+	if req.Source {
+		videoInfo.Raw = rawData(result)
 	}
 
 	return videoInfo, nil
 }
 
 // parseVideoData 解析小红书API返回的视频数据
-func (p *XiaohongshuParser) parseVideoData(data []byte) (*videosdk.VideoInfo, error) {
-	result := gjson.ParseBytes(data)
-
+func (p *XiaohongshuParser) parseVideoData(ctx context.Context, result gjson.Result, noteID, token string) (*videosdk.VideoInfo, error) {
 	// 检查响应是否成功
 	message := result.Get("message").String()
 	if !strings.Contains(message, "成功") {
+		if err := messageError(message); err != nil {
+			return nil, err
+		}
+		if err := xiaohongshuTokenError(message, noteID, token); err != nil {
+			return nil, err
+		}
 		return nil, fmt.Errorf("API返回错误: %s", message)
 	}
 
 	videoData := result.Get("data")
-	if !videoData.Exists() {
-		return nil, errors.New("响应中缺少data字段")
+	if !videoData.IsObject() {
+		return nil, errors.New("响应中没有作品数据")
+	}
+
+	if err := videosdk.CheckSchema(ctx, xiaohongshuDetailSchema, result); err != nil {
+		return nil, err
 	}
 
 	// 解析基本信息
@@ -133,10 +198,10 @@ func (p *XiaohongshuParser) parseVideoData(data []byte) (*videosdk.VideoInfo, er
 	timestamp := videoData.Get("时间戳").String()
 
 	// 解析统计数据
-	collectCount := videoData.Get("收藏数量").Int()
-	commentCount := videoData.Get("评论数量").Int()
-	shareCount := videoData.Get("分享数量").Int()
-	likeCount := videoData.Get("点赞数量").Int()
+	collectCount := parseCount(videoData.Get("收藏数量"))
+	commentCount := parseCount(videoData.Get("评论数量"))
+	shareCount := parseCount(videoData.Get("分享数量"))
+	likeCount := parseCount(videoData.Get("点赞数量"))
 
 	// 解析作者信息
 	authorNickname := videoData.Get("作者昵称").String()
@@ -182,28 +247,15 @@ func (p *XiaohongshuParser) parseVideoData(data []byte) (*videosdk.VideoInfo, er
 		}
 	}
 
-	// 解析创建时间
-	var createTime time.Time
-	if publishTime != "" {
-		// 尝试多种时间格式
-		timeFormats := []string{
-			"2006-01-02 15:04:05",
-			"2006-01-02T15:04:05Z",
-			"2006-01-02",
-		}
-		for _, format := range timeFormats {
-			if parsedTime, err := time.Parse(format, publishTime); err == nil {
-				createTime = parsedTime
-				break
-			}
-		}
-		if createTime.IsZero() {
-			createTime = time.Now()
-		}
-	} else {
-		createTime = time.Now()
+	// 解析创建时间（优先使用时间戳，无法解析时保持零值）
+	createTime := videosdk.ParseTime(timestamp)
+	if createTime.IsZero() {
+		createTime = videosdk.ParseTime(publishTime)
 	}
 
+	// 解析最后更新时间
+	lastUpdateTime := videosdk.ParseTime(updateTime)
+
 	// 确定视频类型
 	var videoType videosdk.VideoType
 	switch workType {
@@ -248,7 +300,7 @@ func (p *XiaohongshuParser) parseVideoData(data []byte) (*videosdk.VideoInfo, er
 		}
 	}
 
-	return &videosdk.VideoInfo{
+	videoInfo := &videosdk.VideoInfo{
 		ID:          videoID,
 		Title:       title,
 		Description: description,
@@ -256,21 +308,23 @@ func (p *XiaohongshuParser) parseVideoData(data []byte) (*videosdk.VideoInfo, er
 		Platform:    videosdk.PlatformXiaohongshu,
 		URL:         workLink,
 		CreateTime:  createTime,
-		Duration:    "00:00:00",
+		UpdateTime:  lastUpdateTime,
+		Duration:    0,
 		Downloads:   downloads,
 		CoverURL:    coverURL,
 		Width:       0,
 		Height:      0,
 		Author: videosdk.AuthorInfo{
-			UID:      authorID,
-			Nickname: authorNickname,
+			UID:           authorID,
+			Nickname:      authorNickname,
+			FollowerCount: videosdk.CountUnknown,
 		},
 		Stats: videosdk.VideoStats{
-			PlayCount:    0,
-			LikeCount:    int64(int(likeCount)),
-			CommentCount: int64(int(commentCount)),
-			ShareCount:   int64(int(shareCount)),
-			CollectCount: int64(int(collectCount)),
+			PlayCount:    videosdk.CountUnknown, // 小红书不公开播放量
+			LikeCount:    likeCount,
+			CommentCount: commentCount,
+			ShareCount:   shareCount,
+			CollectCount: collectCount,
 		},
 		Music: videosdk.MusicInfo{},
 		Tags:  tags,
@@ -283,5 +337,8 @@ func (p *XiaohongshuParser) parseVideoData(data []byte) (*videosdk.VideoInfo, er
 			"gifURLs":      gifURLs,
 			"authorLink":   authorLink,
 		},
-	}, nil
+	}
+	videoInfo.Related = xiaohongshuRelated(videoInfo, authorLink)
+
+	return videoInfo, nil
 }
@@ -0,0 +1,95 @@
+package parsers
+
+import (
+	"net/url"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// 小红书笔记链接中的访问令牌参数
+const (
+	xsecTokenParam  = "xsec_token"
+	xsecSourceParam = "xsec_source"
+)
+
+// tokenKeywords 表示访问令牌缺失或失效的响应关键字
+//
+// 只收录指向令牌的词，"过期"、"expire"等词也会出现在链接或活动过期的消息中，不能单独作为依据。
+var tokenKeywords = []string{"xsec", "token", "令牌"}
+
+// xsecToken 读取链接中的xsec_token和xsec_source
+func xsecToken(rawURL string) (token, source string) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", ""
+	}
+	query := u.Query()
+	return query.Get(xsecTokenParam), query.Get(xsecSourceParam)
+}
+
+// withXsecToken 在链接上设置xsec_token和xsec_source，为空的参数保持链接中原有的值
+func withXsecToken(rawURL, token, source string) string {
+	if token == "" && source == "" {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	query := u.Query()
+	if token != "" {
+		query.Set(xsecTokenParam, token)
+	}
+	if source != "" {
+		query.Set(xsecSourceParam, source)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// xiaohongshuTokenError 根据后端返回的消息识别访问令牌缺失或失效，无法识别时返回nil
+//
+// 只有消息提到xsec或令牌时才返回TokenError，按请求是否带有令牌区分缺失和过期；
+// 其余消息交由messageError和通用错误处理。
+func xiaohongshuTokenError(message, noteID, token string) error {
+	if !containsMessage(message, tokenKeywords) {
+		return nil
+	}
+
+	return videosdk.NewError(videosdk.ErrCodeTokenRequired, &videosdk.TokenError{
+		Platform: videosdk.PlatformXiaohongshu,
+		VideoID:  noteID,
+		Param:    xsecTokenParam,
+		Expired:  token != "",
+		Message:  message,
+	})
+}
+
+// xiaohongshuRelated 生成笔记和作者主页的带令牌链接
+//
+// 只输出后端返回的链接中带有令牌的条目：请求中的令牌可能已经过期，笔记的令牌也不能用于访问作者主页。
+func xiaohongshuRelated(info *videosdk.VideoInfo, authorLink string) []videosdk.RelatedItem {
+	var related []videosdk.RelatedItem
+	if token, source := xsecToken(info.URL); token != "" {
+		related = append(related, videosdk.RelatedItem{
+			Type:   "note",
+			ID:     info.ID,
+			Title:  info.Title,
+			URL:    info.URL,
+			Token:  token,
+			Source: source,
+		})
+	}
+	if token, source := xsecToken(authorLink); token != "" {
+		related = append(related, videosdk.RelatedItem{
+			Type:   "author",
+			ID:     info.Author.UID,
+			Title:  info.Author.Nickname,
+			URL:    authorLink,
+			Token:  token,
+			Source: source,
+		})
+	}
+	return related
+}
@@ -0,0 +1,62 @@
+package parsers
+
+import (
+	"errors"
+	"testing"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+func TestXiaohongshuTokenError(t *testing.T) {
+	tests := []struct {
+		message string
+		token   string
+		want    bool
+		expired bool
+	}{
+		{"获取小红书作品数据失败，xsec_token无效", "", true, false},
+		{"获取小红书作品数据失败，令牌已过期", "abc", true, true},
+		{"Token expired", "abc", true, true},
+		{"获取小红书作品数据失败", "", false, false},
+		{"获取小红书作品数据失败", "abc", false, false},
+		{"网络异常，请稍后重试", "", false, false},
+		{"链接已过期", "abc", false, false},
+		{"活动已过期", "", false, false},
+		{"expired", "abc", false, false},
+	}
+
+	for _, tt := range tests {
+		err := xiaohongshuTokenError(tt.message, "66e1a2b3000000001e01c2d3", tt.token)
+		var tokenErr *videosdk.TokenError
+		if got := errors.As(err, &tokenErr); got != tt.want {
+			t.Errorf("xiaohongshuTokenError(%q) = %v, want token error %v", tt.message, err, tt.want)
+			continue
+		}
+		if tt.want && tokenErr.Expired != tt.expired {
+			t.Errorf("xiaohongshuTokenError(%q).Expired = %v, want %v", tt.message, tokenErr.Expired, tt.expired)
+		}
+	}
+}
+
+func TestXiaohongshuRelated(t *testing.T) {
+	info := &videosdk.VideoInfo{
+		ID:     "66e1a2b3000000001e01c2d3",
+		URL:    "https://www.xiaohongshu.com/explore/66e1a2b3000000001e01c2d3?xsec_token=note&xsec_source=pc_feed",
+		Author: videosdk.AuthorInfo{UID: "5f1e2d3c000000000101a2b3"},
+	}
+
+	related := xiaohongshuRelated(info, "https://www.xiaohongshu.com/user/profile/5f1e2d3c000000000101a2b3")
+	if len(related) != 1 || related[0].Type != "note" || related[0].Token != "note" || related[0].Source != "pc_feed" {
+		t.Errorf("related without author token = %+v, want only note", related)
+	}
+
+	related = xiaohongshuRelated(info, "https://www.xiaohongshu.com/user/profile/5f1e2d3c000000000101a2b3?xsec_token=author&xsec_source=pc_note")
+	if len(related) != 2 || related[1].Type != "author" || related[1].Token != "author" {
+		t.Errorf("related with author token = %+v, want note and author", related)
+	}
+
+	info.URL = "https://www.xiaohongshu.com/explore/66e1a2b3000000001e01c2d3"
+	if related := xiaohongshuRelated(info, ""); related != nil {
+		t.Errorf("related without backend tokens = %+v, want nil", related)
+	}
+}
@@ -0,0 +1,462 @@
+package videosdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ProxyStrategy 代理池的轮换策略
+type ProxyStrategy string
+
+const (
+	ProxyStrategyRoundRobin ProxyStrategy = "round_robin" // 轮询
+	ProxyStrategyRandom     ProxyStrategy = "random"      // 随机
+	ProxyStrategyLeastUsed  ProxyStrategy = "least_used"  // 使用次数最少优先
+)
+
+// pooledProxy 代理池中的单个代理
+type pooledProxy struct {
+	raw string
+	url *url.URL
+
+	uses                int64
+	failures            int64
+	consecutiveFailures int
+	evicted             bool
+	lastError           string
+	lastChecked         time.Time
+	latency             time.Duration
+}
+
+// ProxyStatus 单个代理的状态
+type ProxyStatus struct {
+	URL                 string        `json:"url"`                  // 代理地址（密码已隐藏）
+	Healthy             bool          `json:"healthy"`              // 是否可用
+	Uses                int64         `json:"uses"`                 // 使用次数
+	Failures            int64         `json:"failures"`             // 失败次数
+	ConsecutiveFailures int           `json:"consecutive_failures"` // 连续失败次数
+	LastError           string        `json:"last_error,omitempty"` // 最近一次失败原因
+	LastChecked         time.Time     `json:"last_checked"`         // 最近一次健康检查时间
+	Latency             time.Duration `json:"latency"`              // 最近一次健康检查耗时
+}
+
+// ProxyPool SDK管理的代理池
+//
+// 支持HTTP、HTTPS和SOCKS5代理（均可带用户名密码），代理连续失败达到阈值后被剔除，
+// 直到健康检查再次通过。同一Cookie会尽量固定使用同一个代理，避免账号频繁切换出口IP。
+// 取出的代理既会转发给后端服务，也可以通过Client用于进程内的请求。
+type ProxyPool struct {
+	mu           sync.Mutex
+	strategy     ProxyStrategy
+	proxies      []*pooledProxy
+	sticky       map[string]string
+	next         int
+	maxFailures  int
+	checkURL     string
+	checkTimeout time.Duration
+	rand         *rand.Rand
+	transports   map[string]*http.Transport
+}
+
+// NewProxyPool 创建代理池
+func NewProxyPool(strategy ProxyStrategy) *ProxyPool {
+	if strategy == "" {
+		strategy = ProxyStrategyRoundRobin
+	}
+	return &ProxyPool{
+		strategy:     strategy,
+		sticky:       make(map[string]string),
+		maxFailures:  3,
+		checkURL:     "https://www.douyin.com/",
+		checkTimeout: 10 * time.Second,
+		rand:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		transports:   make(map[string]*http.Transport),
+	}
+}
+
+// SetMaxFailures 设置连续失败多少次后剔除代理
+func (p *ProxyPool) SetMaxFailures(maxFailures int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxFailures = maxFailures
+}
+
+// SetHealthCheck 设置健康检查的探测地址和超时时间
+func (p *ProxyPool) SetHealthCheck(checkURL string, timeout time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checkURL = checkURL
+	p.checkTimeout = timeout
+}
+
+// Add 添加代理，支持http、https、socks5和socks5h协议
+func (p *ProxyPool) Add(rawURL string) error {
+	u, err := ParseProxyURL(rawURL)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, proxy := range p.proxies {
+		if proxy.raw == rawURL {
+			return nil
+		}
+	}
+	p.proxies = append(p.proxies, &pooledProxy{raw: rawURL, url: u})
+	return nil
+}
+
+// Remove 移除代理
+func (p *ProxyPool) Remove(rawURL string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, proxy := range p.proxies {
+		if proxy.raw == rawURL {
+			p.proxies = append(p.proxies[:i:i], p.proxies[i+1:]...)
+			break
+		}
+	}
+	for key, assigned := range p.sticky {
+		if assigned == rawURL {
+			delete(p.sticky, key)
+		}
+	}
+	if transport, ok := p.transports[rawURL]; ok {
+		transport.CloseIdleConnections()
+		delete(p.transports, rawURL)
+	}
+}
+
+// Len 代理数量
+func (p *ProxyPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.proxies)
+}
+
+// Acquire 取出一个可用代理，没有可用代理时返回false
+//
+// stickyKey不为空时（通常为Cookie标识），优先返回之前分配给它且仍可用的代理。
+func (p *ProxyPool) Acquire(stickyKey string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if stickyKey != "" {
+		if assigned, ok := p.sticky[stickyKey]; ok {
+			if proxy := p.find(assigned); proxy != nil && !proxy.evicted {
+				proxy.uses++
+				return proxy.raw, true
+			}
+			delete(p.sticky, stickyKey)
+		}
+	}
+
+	var healthy []*pooledProxy
+	for _, proxy := range p.proxies {
+		if !proxy.evicted {
+			healthy = append(healthy, proxy)
+		}
+	}
+	if len(healthy) == 0 {
+		return "", false
+	}
+
+	var chosen *pooledProxy
+	switch p.strategy {
+	case ProxyStrategyRandom:
+		chosen = healthy[p.rand.Intn(len(healthy))]
+	case ProxyStrategyLeastUsed:
+		chosen = healthy[0]
+		for _, proxy := range healthy[1:] {
+			if proxy.uses < chosen.uses {
+				chosen = proxy
+			}
+		}
+	default:
+		chosen = healthy[p.next%len(healthy)]
+		p.next++
+	}
+
+	chosen.uses++
+	if stickyKey != "" {
+		p.sticky[stickyKey] = chosen.raw
+	}
+	return chosen.raw, true
+}
+
+// ReportSuccess 记录代理请求成功
+func (p *ProxyPool) ReportSuccess(rawURL string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if proxy := p.find(rawURL); proxy != nil {
+		proxy.consecutiveFailures = 0
+	}
+}
+
+// ReportFailure 记录代理连接失败，连续失败达到阈值时剔除代理
+func (p *ProxyPool) ReportFailure(rawURL string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	proxy := p.find(rawURL)
+	if proxy == nil {
+		return
+	}
+
+	proxy.failures++
+	proxy.consecutiveFailures++
+	if err != nil {
+		proxy.lastError = err.Error()
+	}
+	if p.maxFailures > 0 && proxy.consecutiveFailures >= p.maxFailures {
+		proxy.evicted = true
+	}
+}
+
+// CheckHealth 通过每个代理访问探测地址，更新代理的可用状态
+func (p *ProxyPool) CheckHealth(ctx context.Context) {
+	p.mu.Lock()
+	checkURL := p.checkURL
+	timeout := p.checkTimeout
+	proxies := make([]*pooledProxy, len(p.proxies))
+	copy(proxies, p.proxies)
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, proxy := range proxies {
+		wg.Add(1)
+		go func(proxy *pooledProxy) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := probeProxy(ctx, proxy.url, checkURL, timeout)
+			latency := time.Since(start)
+
+			p.mu.Lock()
+			defer p.mu.Unlock()
+
+			proxy.lastChecked = time.Now()
+			proxy.latency = latency
+			if err != nil {
+				proxy.failures++
+				proxy.consecutiveFailures++
+				proxy.lastError = err.Error()
+				proxy.evicted = true
+				return
+			}
+			proxy.consecutiveFailures = 0
+			proxy.lastError = ""
+			proxy.evicted = false
+		}(proxy)
+	}
+	wg.Wait()
+}
+
+// Start 按固定间隔执行健康检查，直到ctx取消
+func (p *ProxyPool) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	p.CheckHealth(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.CheckHealth(ctx)
+		}
+	}
+}
+
+// Status 获取全部代理的状态
+func (p *ProxyPool) Status() []ProxyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	statuses := make([]ProxyStatus, 0, len(p.proxies))
+	for _, proxy := range p.proxies {
+		statuses = append(statuses, ProxyStatus{
+			URL:                 proxy.url.Redacted(),
+			Healthy:             !proxy.evicted,
+			Uses:                proxy.uses,
+			Failures:            proxy.failures,
+			ConsecutiveFailures: proxy.consecutiveFailures,
+			LastError:           proxy.lastError,
+			LastChecked:         proxy.lastChecked,
+			Latency:             proxy.latency,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].URL < statuses[j].URL })
+	return statuses
+}
+
+// Client 创建通过指定代理访问的HTTP客户端，连接失败会自动上报给代理池
+//
+// 同一代理的客户端共享底层连接池。
+func (p *ProxyPool) Client(rawURL string, timeout time.Duration) (*http.Client, error) {
+	p.mu.Lock()
+	transport, ok := p.transports[rawURL]
+	if !ok {
+		var err error
+		if transport, err = NewProxyTransport(rawURL); err != nil {
+			p.mu.Unlock()
+			return nil, err
+		}
+		p.transports[rawURL] = transport
+	}
+	p.mu.Unlock()
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &reportingTransport{
+			base:  transport,
+			pool:  p,
+			proxy: rawURL,
+		},
+	}, nil
+}
+
+// find 查找代理
+func (p *ProxyPool) find(rawURL string) *pooledProxy {
+	for _, proxy := range p.proxies {
+		if proxy.raw == rawURL {
+			return proxy
+		}
+	}
+	return nil
+}
+
+// reportingTransport 将连接失败和成功上报给代理池
+type reportingTransport struct {
+	base  http.RoundTripper
+	pool  *ProxyPool
+	proxy string
+}
+
+// RoundTrip 实现http.RoundTripper接口
+func (t *reportingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		// 调用方主动取消不代表代理故障
+		if req.Context().Err() == nil {
+			t.pool.ReportFailure(t.proxy, err)
+		}
+		return nil, err
+	}
+
+	t.pool.ReportSuccess(t.proxy)
+	return resp, nil
+}
+
+// ParseProxyURL 解析并校验代理地址
+func ParseProxyURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, expected http, https, socks5 or socks5h", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy url: missing host")
+	}
+
+	return u, nil
+}
+
+// NewProxyTransport 创建通过指定代理访问的Transport
+func NewProxyTransport(rawURL string) (*http.Transport, error) {
+	u, err := ParseProxyURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(u)
+	return transport, nil
+}
+
+// probeProxy 通过代理访问探测地址
+func probeProxy(ctx context.Context, proxyURL *url.URL, checkURL string, timeout time.Duration) error {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+	defer transport.CloseIdleConnections()
+
+	client := &http.Client{Timeout: timeout, Transport: transport}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return fmt.Errorf("proxy health check timeout: %w", err)
+		}
+		return fmt.Errorf("proxy health check failed: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusProxyAuthRequired {
+		return fmt.Errorf("proxy health check failed, status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// proxyReporterKey 上下文中代理故障上报器的键
+type proxyReporterKey struct{}
+
+// proxyReporter 将解析过程中经代理发出的请求的连接错误上报给代理池
+type proxyReporter struct {
+	mu       sync.Mutex
+	pool     *ProxyPool
+	proxy    string
+	reported bool
+}
+
+// withProxyReporter 在上下文中附加代理故障上报器
+func withProxyReporter(ctx context.Context, pool *ProxyPool, proxy string) (context.Context, *proxyReporter) {
+	reporter := &proxyReporter{pool: pool, proxy: proxy}
+	return context.WithValue(ctx, proxyReporterKey{}, reporter), reporter
+}
+
+// ReportProxyError 供解析器上报经代理发出的请求的连接错误
+//
+// proxy为请求使用的代理地址，只有它是SDK从代理池分配的代理时才会计入失败次数；调用方主动取消不视为代理故障。
+func ReportProxyError(ctx context.Context, proxy string, err error) {
+	if proxy == "" || err == nil || ctx.Err() != nil {
+		return
+	}
+	reporter, ok := ctx.Value(proxyReporterKey{}).(*proxyReporter)
+	if !ok || reporter.proxy != proxy {
+		return
+	}
+
+	reporter.mu.Lock()
+	reporter.reported = true
+	reporter.mu.Unlock()
+	reporter.pool.ReportFailure(proxy, err)
+}
+
+// hasReported 解析过程中是否已经上报过连接错误
+func (r *proxyReporter) hasReported() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reported
+}
@@ -0,0 +1,326 @@
+package videosdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LimitScope 限流维度
+type LimitScope string
+
+const (
+	LimitScopePlatform LimitScope = "platform" // 按平台
+	LimitScopeBackend  LimitScope = "backend"  // 按后端服务地址
+	LimitScopeCookie   LimitScope = "cookie"   // 按Cookie（账号）
+)
+
+// LimitRule 限流规则
+type LimitRule struct {
+	Rate        float64 // 每秒允许的请求数，<=0表示不限速
+	Burst       int     // 令牌桶容量，<=0时按1处理
+	MaxInFlight int     // 最大并发请求数，<=0表示不限制
+}
+
+// LimitKey 限流对象
+type LimitKey struct {
+	Scope LimitScope // 限流维度
+	Key   string     // 平台名、后端地址或Cookie标识
+}
+
+// LimiterStatus 单个限流器的当前状态
+type LimiterStatus struct {
+	Scope       LimitScope `json:"scope"`         // 限流维度
+	Key         string     `json:"key"`           // 限流对象
+	Rate        float64    `json:"rate"`          // 每秒允许的请求数
+	Burst       int        `json:"burst"`         // 令牌桶容量
+	Tokens      float64    `json:"tokens"`        // 当前可用令牌数（负数表示已被预约）
+	MaxInFlight int        `json:"max_in_flight"` // 最大并发请求数
+	InFlight    int        `json:"in_flight"`     // 当前并发请求数
+	Waiting     int        `json:"waiting"`       // 正在等待的请求数
+}
+
+// limiter 单个限流对象的令牌桶和并发信号量
+type limiter struct {
+	rule     LimitRule
+	tokens   float64
+	last     time.Time
+	sem      chan struct{}
+	waiting  int
+	inFlight int
+}
+
+// newLimiter 创建限流器，令牌桶初始为满
+func newLimiter(rule LimitRule) *limiter {
+	if rule.Burst <= 0 {
+		rule.Burst = 1
+	}
+	l := &limiter{rule: rule, tokens: float64(rule.Burst), last: time.Now()}
+	if rule.MaxInFlight > 0 {
+		l.sem = make(chan struct{}, rule.MaxInFlight)
+	}
+	return l
+}
+
+// refill 按经过的时间补充令牌（调用方需持有锁）
+func (l *limiter) refill(now time.Time) {
+	if l.rule.Rate <= 0 {
+		return
+	}
+	l.tokens += now.Sub(l.last).Seconds() * l.rule.Rate
+	if max := float64(l.rule.Burst); l.tokens > max {
+		l.tokens = max
+	}
+	l.last = now
+}
+
+// RateLimiter 按平台、后端服务和Cookie限流的限流器
+//
+// 每个限流对象同时支持令牌桶限速和最大并发数限制。等待令牌或并发名额时遵守ctx的截止时间：
+// 预计等待时间超过截止时间时立即返回ErrCodeRateLimited错误，而不是等到超时。
+type RateLimiter struct {
+	mu       sync.Mutex
+	rules    map[LimitKey]LimitRule
+	defaults map[LimitScope]LimitRule
+	limiters map[LimitKey]*limiter
+}
+
+// NewRateLimiter 创建限流器
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		rules:    make(map[LimitKey]LimitRule),
+		defaults: make(map[LimitScope]LimitRule),
+		limiters: make(map[LimitKey]*limiter),
+	}
+}
+
+// SetPlatformLimit 设置平台的限流规则
+func (r *RateLimiter) SetPlatformLimit(platform Platform, rule LimitRule) {
+	r.SetLimit(LimitKey{Scope: LimitScopePlatform, Key: string(platform)}, rule)
+}
+
+// SetBackendLimit 设置后端服务地址的限流规则
+func (r *RateLimiter) SetBackendLimit(baseURL string, rule LimitRule) {
+	r.SetLimit(LimitKey{Scope: LimitScopeBackend, Key: baseURL}, rule)
+}
+
+// SetCookieLimit 设置每个Cookie（账号）各自适用的限流规则
+func (r *RateLimiter) SetCookieLimit(rule LimitRule) {
+	r.SetDefaultLimit(LimitScopeCookie, rule)
+}
+
+// SetLimit 设置指定限流对象的规则，已有的限流状态会被重置
+func (r *RateLimiter) SetLimit(key LimitKey, rule LimitRule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules[key] = rule
+	delete(r.limiters, key)
+}
+
+// SetDefaultLimit 设置某一维度下未单独配置的限流对象的默认规则
+func (r *RateLimiter) SetDefaultLimit(scope LimitScope, rule LimitRule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaults[scope] = rule
+	for key := range r.limiters {
+		if _, ok := r.rules[key]; !ok && key.Scope == scope {
+			delete(r.limiters, key)
+		}
+	}
+}
+
+// Acquire 依次获取各限流对象的令牌和并发名额，成功后返回释放并发名额的函数
+//
+// 任一限流对象获取失败时，已获取的并发名额会被释放。未配置规则的限流对象直接放行。
+func (r *RateLimiter) Acquire(ctx context.Context, keys ...LimitKey) (func(), error) {
+	var acquired []*limiter
+	release := func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, l := range acquired {
+			l.inFlight--
+			<-l.sem
+		}
+		acquired = nil
+	}
+
+	for _, key := range keys {
+		if key.Key == "" {
+			continue
+		}
+
+		l := r.limiter(key)
+		if l == nil {
+			continue
+		}
+
+		if err := r.waitToken(ctx, key, l); err != nil {
+			release()
+			return nil, err
+		}
+
+		if l.sem != nil {
+			if err := r.waitSlot(ctx, key, l); err != nil {
+				release()
+				return nil, err
+			}
+			acquired = append(acquired, l)
+		}
+	}
+
+	var once sync.Once
+	return func() { once.Do(release) }, nil
+}
+
+// Status 获取全部已创建限流器的状态
+func (r *RateLimiter) Status() []LimiterStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]LimiterStatus, 0, len(r.limiters))
+	for key, l := range r.limiters {
+		l.refill(now)
+		statuses = append(statuses, LimiterStatus{
+			Scope:       key.Scope,
+			Key:         key.Key,
+			Rate:        l.rule.Rate,
+			Burst:       l.rule.Burst,
+			Tokens:      l.tokens,
+			MaxInFlight: l.rule.MaxInFlight,
+			InFlight:    l.inFlight,
+			Waiting:     l.waiting,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Scope != statuses[j].Scope {
+			return statuses[i].Scope < statuses[j].Scope
+		}
+		return statuses[i].Key < statuses[j].Key
+	})
+	return statuses
+}
+
+// limiter 获取或创建限流对象的限流器，未配置规则时返回nil
+func (r *RateLimiter) limiter(key LimitKey) *limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, ok := r.limiters[key]; ok {
+		return l
+	}
+
+	rule, ok := r.rules[key]
+	if !ok {
+		if rule, ok = r.defaults[key.Scope]; !ok {
+			return nil
+		}
+	}
+
+	l := newLimiter(rule)
+	r.limiters[key] = l
+	return l
+}
+
+// waitToken 预约一个令牌并等待其可用
+func (r *RateLimiter) waitToken(ctx context.Context, key LimitKey, l *limiter) error {
+	if l.rule.Rate <= 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	now := time.Now()
+	l.refill(now)
+
+	var wait time.Duration
+	if l.tokens < 1 {
+		wait = time.Duration((1 - l.tokens) / l.rule.Rate * float64(time.Second))
+	}
+	if deadline, ok := ctx.Deadline(); ok && wait > 0 && now.Add(wait).After(deadline) {
+		r.mu.Unlock()
+		return NewError(ErrCodeRateLimited, fmt.Errorf("%s %s rate limit exceeded, next token in %s", key.Scope, key.Key, wait.Round(time.Millisecond)))
+	}
+
+	// 先扣除令牌完成预约，等待被取消时归还
+	l.tokens--
+	r.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	l.waiting++
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		l.waiting--
+		r.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		r.mu.Lock()
+		l.tokens++
+		r.mu.Unlock()
+		return NewError(ErrorCodeOf(ctx.Err()), fmt.Errorf("waiting for %s %s rate limit: %w", key.Scope, key.Key, ctx.Err()))
+	}
+}
+
+// waitSlot 等待并发名额
+func (r *RateLimiter) waitSlot(ctx context.Context, key LimitKey, l *limiter) error {
+	select {
+	case l.sem <- struct{}{}:
+		r.mu.Lock()
+		l.inFlight++
+		r.mu.Unlock()
+		return nil
+	default:
+	}
+
+	r.mu.Lock()
+	l.waiting++
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		l.waiting--
+		r.mu.Unlock()
+	}()
+
+	select {
+	case l.sem <- struct{}{}:
+		r.mu.Lock()
+		l.inFlight++
+		r.mu.Unlock()
+		return nil
+	case <-ctx.Done():
+		return NewError(ErrorCodeOf(ctx.Err()), fmt.Errorf("waiting for %s %s concurrency slot: %w", key.Scope, key.Key, ctx.Err()))
+	}
+}
+
+// rateLimiterKey 上下文中限流器的键
+type rateLimiterKey struct{}
+
+// withRateLimiter 将限流器放入上下文，供故障转移解析器按实际使用的后端限流
+func withRateLimiter(ctx context.Context, r *RateLimiter) context.Context {
+	if r == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, rateLimiterKey{}, r)
+}
+
+// acquireBackendLimit 按上下文中的限流器获取后端服务的令牌和并发名额
+func acquireBackendLimit(ctx context.Context, baseURL string) (func(), error) {
+	r, ok := ctx.Value(rateLimiterKey{}).(*RateLimiter)
+	if !ok || baseURL == "" {
+		return func() {}, nil
+	}
+	return r.Acquire(ctx, LimitKey{Scope: LimitScopeBackend, Key: baseURL})
+}
@@ -0,0 +1,208 @@
+package videosdk
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/tidwall/gjson"
+)
+
+// SchemaMode 后端响应结构校验模式
+type SchemaMode string
+
+const (
+	SchemaModeLenient SchemaMode = "lenient" // 宽松模式（默认）：结构不符时记录警告并继续解析
+	SchemaModeStrict  SchemaMode = "strict"  // 严格模式：结构不符时返回SchemaError
+)
+
+// FieldType 字段的期望类型
+type FieldType string
+
+const (
+	FieldString FieldType = "string" // 字符串
+	FieldNumber FieldType = "number" // 数字
+	FieldBool   FieldType = "bool"   // 布尔值
+	FieldArray  FieldType = "array"  // 数组
+	FieldObject FieldType = "object" // 对象
+	FieldCount  FieldType = "count"  // 数量，数字或"1.2万"这样的字符串
+	FieldAny    FieldType = "any"    // 任意类型，只要求字段存在
+)
+
+// FieldSpec 期望的字段
+type FieldSpec struct {
+	Path    string    // gjson路径，如"data.作品ID"
+	Type    FieldType // 期望类型，为空时等同FieldAny；值为null时不检查类型
+	Aliases []string  // 已知的其他路径，字段缺失而别名存在时视为被重命名
+}
+
+// Schema 后端接口响应的期望结构
+type Schema struct {
+	Name   string      // 名称，如"douyin.detail"
+	Fields []FieldSpec // 期望的字段
+}
+
+// SchemaIssueKind 结构问题类别
+type SchemaIssueKind string
+
+const (
+	SchemaIssueMissing      SchemaIssueKind = "missing"       // 字段缺失
+	SchemaIssueRenamed      SchemaIssueKind = "renamed"       // 字段被重命名
+	SchemaIssueTypeMismatch SchemaIssueKind = "type_mismatch" // 字段类型不符
+)
+
+// SchemaIssue 一个结构问题
+type SchemaIssue struct {
+	Path      string          `json:"path"`                 // 期望的字段路径
+	Kind      SchemaIssueKind `json:"kind"`                 // 问题类别
+	Expected  FieldType       `json:"expected,omitempty"`   // 期望类型
+	Actual    string          `json:"actual,omitempty"`     // 实际类型
+	RenamedTo string          `json:"renamed_to,omitempty"` // 重命名后的路径
+}
+
+// String 获取问题描述
+func (i SchemaIssue) String() string {
+	switch i.Kind {
+	case SchemaIssueRenamed:
+		return fmt.Sprintf("%s renamed to %s", i.Path, i.RenamedTo)
+	case SchemaIssueTypeMismatch:
+		return fmt.Sprintf("%s expected %s, got %s", i.Path, i.Expected, i.Actual)
+	default:
+		return fmt.Sprintf("%s missing", i.Path)
+	}
+}
+
+// SchemaError 严格模式下后端响应与期望结构不符的错误
+type SchemaError struct {
+	Schema string        // 结构名称
+	Issues []SchemaIssue // 全部结构问题
+}
+
+// Error 实现error接口
+func (e *SchemaError) Error() string {
+	issues := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		issues[i] = issue.String()
+	}
+	return fmt.Sprintf("backend response does not match schema %s: %s", e.Schema, strings.Join(issues, "; "))
+}
+
+// Validate 校验响应是否符合期望结构，返回全部结构问题
+func (s *Schema) Validate(result gjson.Result) []SchemaIssue {
+	var issues []SchemaIssue
+	for _, field := range s.Fields {
+		value := result.Get(field.Path)
+		if !value.Exists() {
+			issue := SchemaIssue{Path: field.Path, Kind: SchemaIssueMissing, Expected: field.Type}
+			for _, alias := range field.Aliases {
+				if result.Get(alias).Exists() {
+					issue.Kind = SchemaIssueRenamed
+					issue.RenamedTo = alias
+					break
+				}
+			}
+			issues = append(issues, issue)
+			continue
+		}
+
+		if actual := jsonType(value); !matchesType(field.Type, actual) {
+			issues = append(issues, SchemaIssue{
+				Path:     field.Path,
+				Kind:     SchemaIssueTypeMismatch,
+				Expected: field.Type,
+				Actual:   string(actual),
+			})
+		}
+	}
+	return issues
+}
+
+// jsonType 获取值的JSON类型
+func jsonType(value gjson.Result) FieldType {
+	switch {
+	case value.Type == gjson.String:
+		return FieldString
+	case value.Type == gjson.Number:
+		return FieldNumber
+	case value.IsBool():
+		return FieldBool
+	case value.IsArray():
+		return FieldArray
+	case value.IsObject():
+		return FieldObject
+	default:
+		return "null"
+	}
+}
+
+// matchesType 判断实际类型是否符合期望类型，null符合任意类型
+func matchesType(expected, actual FieldType) bool {
+	switch expected {
+	case "", FieldAny:
+		return true
+	case FieldCount:
+		return actual == FieldNumber || actual == FieldString || actual == "null"
+	default:
+		return actual == expected || actual == "null"
+	}
+}
+
+// schemaCheckerKey 上下文中结构校验器的键
+type schemaCheckerKey struct{}
+
+// schemaChecker 按模式处理结构问题并收集宽松模式下的警告
+type schemaChecker struct {
+	mode     SchemaMode
+	mu       sync.Mutex
+	warnings []string
+}
+
+// withSchemaChecker 在上下文中附加结构校验器
+func withSchemaChecker(ctx context.Context, mode SchemaMode) (context.Context, *schemaChecker) {
+	checker := &schemaChecker{mode: mode}
+	return context.WithValue(ctx, schemaCheckerKey{}, checker), checker
+}
+
+// Warnings 获取收集到的警告
+func (c *schemaChecker) Warnings() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.warnings...)
+}
+
+// CheckSchema 供解析器校验后端响应的结构
+//
+// 发现结构问题时记录日志和指标；严格模式下返回ErrCodeSchemaMismatch类别的SchemaError，
+// 宽松模式下问题作为警告附加到ParseResponse.Warnings并返回nil。
+func CheckSchema(ctx context.Context, schema *Schema, result gjson.Result) error {
+	issues := schema.Validate(result)
+	if len(issues) == 0 {
+		return nil
+	}
+
+	metrics := MetricsFromContext(ctx)
+	messages := make([]string, len(issues))
+	for i, issue := range issues {
+		metrics.SchemaDrift(schema.Name, issue.Path, issue.Kind)
+		messages[i] = issue.String()
+	}
+	LoggerFromContext(ctx).WarnContext(ctx, "backend schema drift",
+		slog.String("schema", schema.Name),
+		slog.Any("issues", messages),
+	)
+
+	checker, _ := ctx.Value(schemaCheckerKey{}).(*schemaChecker)
+	if checker != nil && checker.mode == SchemaModeStrict {
+		return NewError(ErrCodeSchemaMismatch, &SchemaError{Schema: schema.Name, Issues: issues})
+	}
+	if checker != nil {
+		checker.mu.Lock()
+		for _, message := range messages {
+			checker.warnings = append(checker.warnings, schema.Name+": "+message)
+		}
+		checker.mu.Unlock()
+	}
+	return nil
+}
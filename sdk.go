@@ -3,24 +3,44 @@ package videosdk
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 )
 
 // VideoSDK SDK主实现
 type VideoSDK struct {
-	parsers   map[Platform]Parser
-	mu        sync.RWMutex
-	timeout   time.Duration
-	userAgent string
+	parsers          map[Platform]Parser
+	mu               sync.RWMutex
+	timeout          time.Duration
+	userAgent        string
+	batchConcurrency int
+	cookieJar        *CookieJar
+	cookiePool       *CookiePool
+	proxyPool        *ProxyPool
+	rateLimiter      *RateLimiter
+
+	middleware         []Middleware
+	platformMiddleware map[Platform][]Middleware
+	tracer             Tracer
+	metrics            *Metrics
+	logger             *slog.Logger
+	schemaMode         SchemaMode
+	shortLinks         *ShortLinkResolver
 }
 
 // NewSDK 创建新的SDK实例
-func NewSDK() SDK {
+//
+// 返回具体类型*VideoSDK，Cookie、代理、限流、中间件、追踪等配置方法只在*VideoSDK上提供，不属于SDK接口。
+func NewSDK() *VideoSDK {
 	return &VideoSDK{
-		parsers:   make(map[Platform]Parser),
-		timeout:   30 * time.Second,
-		userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36",
+		parsers:            make(map[Platform]Parser),
+		platformMiddleware: make(map[Platform][]Middleware),
+		timeout:            30 * time.Second,
+		userAgent:          "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36",
+		batchConcurrency:   4,
+		schemaMode:         SchemaModeLenient,
+		shortLinks:         NewShortLinkResolver(),
 	}
 }
 
@@ -43,6 +63,9 @@ func (s *VideoSDK) RegisterParser(parser Parser) error {
 }
 
 // ParseVideo 解析视频信息
+//
+// 请求依次经过全局中间件、平台中间件，最后由SDK附加Cookie和代理、验证参数、限流并调用解析器。
+// 中间件收到的是请求的副本，可以直接修改而不影响调用方的数据。
 func (s *VideoSDK) ParseVideo(ctx context.Context, req *ParseRequest) (*ParseResponse, error) {
 	start := time.Now()
 	response := &ParseResponse{
@@ -51,55 +74,236 @@ func (s *VideoSDK) ParseVideo(ctx context.Context, req *ParseRequest) (*ParseRes
 
 	// 参数验证
 	if req == nil {
-		response.Success = false
-		response.Error = "request cannot be nil"
-		return response, fmt.Errorf("request cannot be nil")
+		return s.fail(response, ErrCodeInvalidRequest, fmt.Errorf("request cannot be nil"))
 	}
 
 	if req.Platform == "" {
-		response.Success = false
-		response.Error = "platform is required"
-		return response, fmt.Errorf("platform is required")
+		return s.fail(response, ErrCodeInvalidRequest, fmt.Errorf("platform is required"))
 	}
 
 	// 获取解析器
 	s.mu.RLock()
 	parser, exists := s.parsers[req.Platform]
+	timeout := s.timeout
+	middleware := append(s.middleware[:len(s.middleware):len(s.middleware)], s.platformMiddleware[req.Platform]...)
+	tracer := s.tracer
+	metrics := s.metrics
+	logger := s.logger
+	schemaMode := s.schemaMode
+	shortLinks := s.shortLinks
 	s.mu.RUnlock()
 
 	if !exists {
-		response.Success = false
-		response.Error = fmt.Sprintf("platform %s is not supported", req.Platform)
-		return response, fmt.Errorf("platform %s is not supported", req.Platform)
+		return s.fail(response, ErrCodeUnsupportedPlatform, fmt.Errorf("platform %s is not supported", req.Platform))
+	}
+
+	// 追踪器和指标通过上下文传给解析器，用于记录短链接解析和后端请求
+	ctx = withLogger(withMetrics(withTracer(ctx, tracer), metrics), logger)
+	ctx = withShortLinkResolver(ctx, shortLinks)
+	ctx, checker := withSchemaChecker(ctx, schemaMode)
+	ctx, span := StartSpan(ctx, "VideoSDK.ParseVideo", Attribute{Key: "platform", Value: string(req.Platform)})
+	defer span.End()
+
+	// 设置超时上下文，中间件的耗时同样计入超时
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	parse := Chain(middleware...)(func(ctx context.Context, req *ParseRequest) (*VideoInfo, error) {
+		return s.parse(ctx, parser, req)
+	})
+
+	log := LoggerFromContext(ctx)
+	log.DebugContext(ctx, "parse request", slog.Any("request", req))
+
+	request := *req
+	videoInfo, err := parse(ctx, &request)
+	if err == nil && videoInfo == nil {
+		err = NewError(ErrCodeParseFailed, fmt.Errorf("failed to parse video: no result"))
+	}
+	elapsed := time.Since(start)
+	response.Warnings = checker.Warnings()
+	metrics.ObserveParse(req.Platform, ErrorCodeOf(err), elapsed)
+	if err != nil {
+		span.SetAttributes(Attribute{Key: "error.code", Value: string(ErrorCodeOf(err))})
+		span.RecordError(err)
+		log.WarnContext(ctx, "parse failed",
+			slog.String("platform", string(req.Platform)),
+			slog.String("code", string(ErrorCodeOf(err))),
+			slog.Duration("elapsed", elapsed),
+			slog.Any("error", err),
+		)
+		return s.fail(response, ErrorCodeOf(err), err)
+	}
+	span.SetAttributes(Attribute{Key: "video.id", Value: videoInfo.ID})
+	log.InfoContext(ctx, "parse succeeded",
+		slog.String("platform", string(req.Platform)),
+		slog.String("video_id", videoInfo.ID),
+		slog.Duration("elapsed", elapsed),
+	)
+
+	response.Success = true
+	response.Message = "解析成功"
+	response.Data = videoInfo
+
+	return response, nil
+}
+
+// parse 附加Cookie和代理、验证参数、限流后调用解析器，是中间件链的最内层
+func (s *VideoSDK) parse(ctx context.Context, parser Parser, req *ParseRequest) (*VideoInfo, error) {
+	s.mu.RLock()
+	cookieJar := s.cookieJar
+	cookiePool := s.cookiePool
+	proxyPool := s.proxyPool
+	rateLimiter := s.rateLimiter
+	s.mu.RUnlock()
+
+	// 未提供Cookie时依次从Cookie池和CookieJar中自动附加（复制请求，避免修改调用方的数据）
+	var lease *CookieLease
+	if req.Cookie == "" && cookiePool != nil {
+		if l, ok := cookiePool.Acquire(req.Platform); ok {
+			lease = l
+			withCookie := *req
+			withCookie.Cookie = l.Cookie
+			req = &withCookie
+		}
+	}
+	if req.Cookie == "" && cookieJar != nil {
+		if cookie, err := cookieJar.Header(req.Platform); err == nil && cookie != "" {
+			withCookie := *req
+			withCookie.Cookie = cookie
+			req = &withCookie
+		}
+	}
+
+	// 未指定代理时从代理池中分配，同一Cookie固定使用同一代理
+	var proxy string
+	if req.Proxy == "" && proxyPool != nil {
+		if p, ok := proxyPool.Acquire(cookieKey(req.Cookie)); ok {
+			proxy = p
+			withProxy := *req
+			withProxy.Proxy = p
+			req = &withProxy
+		}
 	}
 
 	// 验证请求参数
 	if err := parser.ValidateRequest(req); err != nil {
-		response.Success = false
-		response.Error = fmt.Sprintf("request validation failed: %v", err)
-		return response, fmt.Errorf("request validation failed: %w", err)
+		return nil, NewError(ErrCodeInvalidRequest, fmt.Errorf("request validation failed: %w", err))
 	}
 
-	// 设置超时上下文
-	ctx, cancel := context.WithTimeout(ctx, s.timeout)
-	defer cancel()
+	// 按平台、后端服务和Cookie限流，等待时间计入超时
+	if rateLimiter != nil {
+		release, err := rateLimiter.Acquire(ctx, limitKeys(parser, req)...)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		ctx = withRateLimiter(ctx, rateLimiter)
+	}
 
-	// 解析视频信息
+	// 解析视频信息，并将解析器通过ReportSetCookies上报的Set-Cookie合并回CookieJar
+	ctx, recorder := withCookieRecorder(ctx)
+	var reporter *proxyReporter
+	if proxy != "" {
+		ctx, reporter = withProxyReporter(ctx, proxyPool, proxy)
+	}
 	videoInfo, err := parser.ParseVideo(ctx, req)
+	if cookieJar != nil {
+		recorder.mu.Lock()
+		_ = cookieJar.Update(req.Platform, recorder.cookies)
+		recorder.mu.Unlock()
+	}
+	if lease != nil {
+		// 只有登录、验证类错误说明Cookie失效，超时等其他错误不影响Cookie健康状态
+		if err == nil {
+			cookiePool.ReportSuccess(lease)
+		} else if IsCookieFailure(err) {
+			cookiePool.ReportFailure(lease, err)
+		}
+	}
+	if proxy != "" {
+		// 连接错误已由解析器通过ReportProxyError上报，这里只补充后端报告的代理故障
+		if err == nil {
+			proxyPool.ReportSuccess(proxy)
+		} else if ErrorCodeOf(err) == ErrCodeProxyFailed && !reporter.hasReported() {
+			proxyPool.ReportFailure(proxy, err)
+		}
+	}
 	if err != nil {
-		response.Success = false
-		response.Error = fmt.Sprintf("failed to parse video: %v", err)
-		return response, fmt.Errorf("failed to parse video: %w", err)
+		return nil, NewError(ErrorCodeOf(err), fmt.Errorf("failed to parse video: %w", err))
 	}
 
 	// 设置平台信息
 	videoInfo.Platform = req.Platform
 
-	response.Success = true
-	response.Message = "解析成功"
-	response.Data = videoInfo
+	return videoInfo, nil
+}
 
-	return response, nil
+// limitKeys 请求对应的限流对象
+func limitKeys(parser Parser, req *ParseRequest) []LimitKey {
+	keys := []LimitKey{{Scope: LimitScopePlatform, Key: string(req.Platform)}}
+	if backend, ok := parser.(BackendProvider); ok {
+		keys = append(keys, LimitKey{Scope: LimitScopeBackend, Key: backend.BaseURL()})
+	}
+	keys = append(keys, LimitKey{Scope: LimitScopeCookie, Key: cookieKey(req.Cookie)})
+	return keys
+}
+
+// fail 填充失败响应并返回带错误类别的错误
+func (s *VideoSDK) fail(response *ParseResponse, code ErrorCode, err error) (*ParseResponse, error) {
+	response.Success = false
+	response.Code = code
+	response.Error = err.Error()
+	return response, NewError(code, err)
+}
+
+// ParseBatch 并发解析多个视频，返回结果与请求一一对应
+//
+// 指向同一作品的请求（见RequestKey）只解析一次，各自得到结果的副本。
+func (s *VideoSDK) ParseBatch(ctx context.Context, reqs []*ParseRequest) []*ParseResponse {
+	unique, index := DedupeRequests(reqs)
+	responses := make([]*ParseResponse, len(unique))
+
+	s.mu.RLock()
+	concurrency := s.batchConcurrency
+	s.mu.RUnlock()
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, req := range unique {
+		wg.Add(1)
+		go func(i int, req *ParseRequest) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				responses[i], _ = s.fail(&ParseResponse{Time: time.Now()}, ErrorCodeOf(ctx.Err()), ctx.Err())
+				return
+			}
+
+			responses[i], _ = s.ParseVideo(ctx, req)
+		}(i, req)
+	}
+	wg.Wait()
+
+	results := make([]*ParseResponse, len(reqs))
+	used := make([]bool, len(unique))
+	for i, j := range index {
+		if !used[j] {
+			used[j] = true
+			results[i] = responses[j]
+			continue
+		}
+		duplicate := *responses[j]
+		duplicate.Data = cloneVideoInfo(duplicate.Data)
+		results[i] = &duplicate
+	}
+	return results
 }
 
 // GetSupportedPlatforms 获取支持的平台列表
@@ -122,6 +326,102 @@ func (s *VideoSDK) SetTimeout(timeout time.Duration) {
 	s.timeout = timeout
 }
 
+// SetBatchConcurrency 设置批量解析的最大并发数
+func (s *VideoSDK) SetBatchConcurrency(concurrency int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batchConcurrency = concurrency
+}
+
+// SetCookieJar 设置Cookie管理器，请求未提供Cookie时自动使用其中对应平台的Cookie
+func (s *VideoSDK) SetCookieJar(jar *CookieJar) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cookieJar = jar
+}
+
+// SetCookiePool 设置多账号Cookie池，请求未提供Cookie时优先从池中轮换取用
+func (s *VideoSDK) SetCookiePool(pool *CookiePool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cookiePool = pool
+}
+
+// SetProxyPool 设置代理池，请求未指定代理时从池中分配
+func (s *VideoSDK) SetProxyPool(pool *ProxyPool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.proxyPool = pool
+}
+
+// SetRateLimiter 设置限流器，解析前按平台、后端服务和Cookie等待令牌和并发名额
+func (s *VideoSDK) SetRateLimiter(limiter *RateLimiter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimiter = limiter
+}
+
+// Use 注册全局中间件，先注册的位于外层
+func (s *VideoSDK) Use(middleware ...Middleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.middleware = append(s.middleware, middleware...)
+}
+
+// UsePlatform 注册只作用于指定平台的中间件，位于全局中间件内层
+func (s *VideoSDK) UsePlatform(platform Platform, middleware ...Middleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.platformMiddleware[platform] = append(s.platformMiddleware[platform], middleware...)
+}
+
+// SetTracer 设置追踪器，为解析请求、短链接解析和后端请求创建Span
+func (s *VideoSDK) SetTracer(tracer Tracer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tracer = tracer
+}
+
+// SetMetrics 设置指标集合，记录请求数、错误数、耗时、后端请求和重试次数
+func (s *VideoSDK) SetMetrics(metrics *Metrics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metrics = metrics
+}
+
+// SetLogger 设置结构化日志，日志级别由logger的Handler决定；传nil关闭日志
+//
+// 输出前会自动隐去Cookie、Token和代理密码等敏感信息。
+func (s *VideoSDK) SetLogger(logger *slog.Logger) {
+	if logger != nil {
+		logger = slog.New(NewRedactingHandler(logger.Handler()))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger = logger
+}
+
+// SetSchemaMode 设置后端响应结构校验模式，默认SchemaModeLenient
+//
+// 严格模式下后端响应缺少字段、字段被重命名或类型不符时返回ErrCodeSchemaMismatch错误；
+// 宽松模式下继续解析，并将问题附加到ParseResponse.Warnings。
+func (s *VideoSDK) SetSchemaMode(mode SchemaMode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schemaMode = mode
+}
+
+// SetShortLinkResolver 设置短链接解析器，默认使用NewShortLinkResolver创建的解析器
+//
+// 解析器在提取作品ID前通过它直接跟随短链接的跳转；设置为nil时禁用，
+// 抖音短链接改由后端的分享链接接口解析，其他平台的短链接原样交给后端。
+func (s *VideoSDK) SetShortLinkResolver(resolver *ShortLinkResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shortLinks = resolver
+}
+
 // SetUserAgent 设置User-Agent
 func (s *VideoSDK) SetUserAgent(userAgent string) {
 	s.mu.Lock()
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/jobs"
+)
+
+// handleJobs 提交任务或列出任务
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var req jobs.Request
+		if err := decodeJSON(r, &req); err != nil {
+			writeError(w, videosdk.ErrCodeInvalidRequest, err)
+			return
+		}
+
+		job, err := s.config.Jobs.Submit(r.Context(), &req)
+		if err != nil {
+			writeJobError(w, err)
+			return
+		}
+		w.Header().Set("Location", "/jobs/"+job.ID)
+		writeJSON(w, http.StatusAccepted, job)
+
+	case http.MethodGet:
+		filter := jobs.Filter{Limit: 100}
+		query := r.URL.Query()
+		for _, status := range strings.Split(query.Get("status"), ",") {
+			if status != "" {
+				filter.Status = append(filter.Status, jobs.Status(status))
+			}
+		}
+		if limit := query.Get("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil || n <= 0 {
+				writeError(w, videosdk.ErrCodeInvalidRequest, fmt.Errorf("invalid limit: %q", limit))
+				return
+			}
+			filter.Limit = n
+		}
+
+		list, err := s.config.Jobs.List(r.Context(), filter)
+		if err != nil {
+			writeJobError(w, err)
+			return
+		}
+		if list == nil {
+			list = []*jobs.Job{}
+		}
+		writeJSON(w, http.StatusOK, &JobsResponse{Jobs: list})
+
+	default:
+		allowMethod(w, r, http.MethodGet+", "+http.MethodPost)
+	}
+}
+
+// handleJob 查询、删除或取消单个任务
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/jobs/"), "/")
+	if id == "" || (action != "" && action != "cancel") {
+		http.NotFound(w, r)
+		return
+	}
+
+	if action == "cancel" {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+		job, err := s.config.Jobs.Cancel(r.Context(), id)
+		if err != nil {
+			writeJobError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, job)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		job, err := s.config.Jobs.Get(r.Context(), id)
+		if err != nil {
+			writeJobError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, job)
+
+	case http.MethodDelete:
+		if err := s.config.Jobs.Delete(r.Context(), id); err != nil {
+			writeJobError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		allowMethod(w, r, http.MethodGet+", "+http.MethodDelete)
+	}
+}
+
+// writeJobError 输出任务接口的错误响应
+func writeJobError(w http.ResponseWriter, err error) {
+	var sdkErr *videosdk.Error
+	if errors.As(err, &sdkErr) {
+		writeError(w, sdkErr.Code, err)
+		return
+	}
+
+	// 任务不存在或状态不允许为请求错误，其余为存储错误
+	status := http.StatusInternalServerError
+	var code videosdk.ErrorCode
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		status, code = http.StatusNotFound, videosdk.ErrCodeInvalidRequest
+	case errors.Is(err, jobs.ErrFinished), errors.Is(err, jobs.ErrNotFinished):
+		status, code = http.StatusConflict, videosdk.ErrCodeInvalidRequest
+	}
+
+	writeJSON(w, status, &videosdk.ParseResponse{
+		Success: false,
+		Code:    code,
+		Error:   err.Error(),
+		Time:    time.Now(),
+	})
+}
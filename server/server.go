@@ -0,0 +1,446 @@
+// Package server 以JSON HTTP API的形式对外提供SDK的解析和下载能力
+//
+// Server实现了http.Handler，可以直接挂载到已有的HTTP服务中，
+// 也可以通过ListenAndServe独立运行：
+//
+//	POST /parse        解析单个作品，返回ParseResponse
+//	POST /parse/batch  批量解析，返回与请求一一对应的ParseResponse列表
+//	POST /download     解析作品并以流的形式返回指定序号的媒体文件
+//	GET  /platforms    获取支持的平台列表
+//	POST /jobs         提交异步任务（需设置Config.Jobs），GET /jobs列出任务
+//	GET  /jobs/{id}    查询任务状态和进度，DELETE删除已结束的任务
+//	POST /jobs/{id}/cancel 取消任务
+//	POST /tracker/watchlist 将作品加入统计追踪列表（需设置Config.Tracker），GET列出追踪列表
+//	DELETE /tracker/watchlist/{platform}/{id} 将作品移出追踪列表
+//	GET  /tracker/series/{platform}/{id} 获取作品的统计时间序列，format=csv时导出CSV
+//	GET  /tracker/export 导出追踪列表中全部作品的时间序列（format=json或csv）
+//	GET  /healthz      健康检查（无需鉴权）
+//	GET  /metrics      Prometheus格式的指标（需设置Config.Metrics）
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/jobs"
+	"github.com/caojianfei/parser/storage"
+	"github.com/caojianfei/parser/tracker"
+)
+
+// Config 服务配置
+type Config struct {
+	APIKeys         []string             // 允许访问的API Key，为空时不校验
+	MaxBodyBytes    int64                // 请求体大小上限，默认1MB
+	MaxBatchSize    int                  // 单次批量解析的最大请求数，默认50
+	AllowedOrigins  []string             // CORS允许的来源，包含"*"时允许全部来源
+	Downloader      *videosdk.Downloader // 媒体下载器，默认使用videosdk.NewDownloader()
+	ShutdownTimeout time.Duration        // 优雅关闭的最长等待时间，默认10秒
+	Metrics         *videosdk.Metrics    // 设置后通过GET /metrics以Prometheus文本格式导出指标
+	Jobs            *jobs.Manager        // 设置后提供/jobs异步任务接口，需要已调用Start
+	Tracker         *tracker.Tracker     // 设置后提供/tracker统计追踪接口
+}
+
+// BatchRequest 批量解析请求
+type BatchRequest struct {
+	Requests []*videosdk.ParseRequest `json:"requests"` // 解析请求列表
+}
+
+// BatchResponse 批量解析响应
+type BatchResponse struct {
+	Responses []*videosdk.ParseResponse `json:"responses"` // 与请求一一对应的解析结果
+}
+
+// DownloadRequest 下载请求
+type DownloadRequest struct {
+	videosdk.ParseRequest
+	Index int `json:"index"` // 要下载的媒体序号（从0开始）
+}
+
+// JobsResponse 任务列表响应
+type JobsResponse struct {
+	Jobs []*jobs.Job `json:"jobs"` // 按提交时间倒序排列的任务
+}
+
+// WatchRequest 加入追踪列表的请求
+type WatchRequest struct {
+	Platform videosdk.Platform `json:"platform"` // 平台，设置URL时可省略
+	WorkID   string            `json:"work_id"`  // 作品ID，设置URL时可省略
+	URL      string            `json:"url"`      // 作品链接（小红书需要带xsec_token的链接）
+	Interval string            `json:"interval"` // 采集间隔，如"30m"，为空时使用默认间隔
+}
+
+// WatchlistResponse 追踪列表响应
+type WatchlistResponse struct {
+	Items []*storage.WatchItem `json:"items"` // 按下次采集时间升序排列的作品
+}
+
+// PlatformsResponse 平台列表响应
+type PlatformsResponse struct {
+	Platforms []videosdk.Platform `json:"platforms"` // 支持的平台
+}
+
+// Server SDK的HTTP服务
+type Server struct {
+	sdk    videosdk.SDK
+	config Config
+	mux    *http.ServeMux
+}
+
+// New 创建HTTP服务
+func New(sdk videosdk.SDK, config Config) *Server {
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = 1 << 20
+	}
+	if config.MaxBatchSize <= 0 {
+		config.MaxBatchSize = 50
+	}
+	if config.Downloader == nil {
+		config.Downloader = videosdk.NewDownloader()
+	}
+	if config.ShutdownTimeout <= 0 {
+		config.ShutdownTimeout = 10 * time.Second
+	}
+
+	s := &Server{
+		sdk:    sdk,
+		config: config,
+		mux:    http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("/parse", s.handleParse)
+	s.mux.HandleFunc("/parse/batch", s.handleBatch)
+	s.mux.HandleFunc("/download", s.handleDownload)
+	s.mux.HandleFunc("/platforms", s.handlePlatforms)
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	if config.Metrics != nil {
+		s.mux.Handle("/metrics", config.Metrics)
+	}
+	if config.Jobs != nil {
+		s.mux.HandleFunc("/jobs", s.handleJobs)
+		s.mux.HandleFunc("/jobs/", s.handleJob)
+	}
+	if config.Tracker != nil {
+		s.mux.HandleFunc("/tracker/watchlist", s.handleWatchlist)
+		s.mux.HandleFunc("/tracker/watchlist/", s.handleWatchItem)
+		s.mux.HandleFunc("/tracker/series/", s.handleSeries)
+		s.mux.HandleFunc("/tracker/export", s.handleExport)
+	}
+
+	return s
+}
+
+// ServeHTTP 实现http.Handler接口
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.applyCORS(w, r) {
+		return
+	}
+
+	if r.URL.Path != "/healthz" && !s.authorized(r) {
+		writeError(w, videosdk.ErrCodeUnauthorized, fmt.Errorf("invalid or missing api key"))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.config.MaxBodyBytes)
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe 在指定地址启动服务，ctx取消时优雅关闭
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown server: %w", err)
+	}
+
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// handleParse 处理单个解析请求
+func (s *Server) handleParse(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req videosdk.ParseRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, videosdk.ErrCodeInvalidRequest, err)
+		return
+	}
+
+	resp := s.parseVideo(r.Context(), &req)
+	writeJSON(w, statusForCode(resp.Code), resp)
+}
+
+// handleBatch 处理批量解析请求
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req BatchRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, videosdk.ErrCodeInvalidRequest, err)
+		return
+	}
+
+	if len(req.Requests) == 0 {
+		writeError(w, videosdk.ErrCodeInvalidRequest, fmt.Errorf("requests cannot be empty"))
+		return
+	}
+	if len(req.Requests) > s.config.MaxBatchSize {
+		writeError(w, videosdk.ErrCodeInvalidRequest, fmt.Errorf("too many requests, max batch size is %d", s.config.MaxBatchSize))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &BatchResponse{
+		Responses: s.parseBatch(r.Context(), req.Requests),
+	})
+}
+
+// parseBatch 批量解析，SDK未实现videosdk.BatchSDK时逐个解析
+func (s *Server) parseBatch(ctx context.Context, reqs []*videosdk.ParseRequest) []*videosdk.ParseResponse {
+	if batch, ok := s.sdk.(videosdk.BatchSDK); ok {
+		return batch.ParseBatch(ctx, reqs)
+	}
+
+	responses := make([]*videosdk.ParseResponse, len(reqs))
+	for i, req := range reqs {
+		responses[i] = s.parseVideo(ctx, req)
+	}
+	return responses
+}
+
+// parseVideo 解析单个请求，SDK实现只返回错误（响应为nil）时根据错误构造失败响应
+func (s *Server) parseVideo(ctx context.Context, req *videosdk.ParseRequest) *videosdk.ParseResponse {
+	resp, err := s.sdk.ParseVideo(ctx, req)
+	if resp == nil {
+		resp = &videosdk.ParseResponse{Code: videosdk.ErrorCodeOf(err), Time: time.Now()}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+	}
+	return resp
+}
+
+// handleDownload 解析作品并以流的形式返回媒体文件
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req DownloadRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, videosdk.ErrCodeInvalidRequest, err)
+		return
+	}
+
+	resp := s.parseVideo(r.Context(), &req.ParseRequest)
+	if !resp.Success || resp.Data == nil {
+		writeJSON(w, statusForCode(resp.Code), resp)
+		return
+	}
+
+	if req.Index < 0 || req.Index >= len(resp.Data.Downloads) {
+		writeError(w, videosdk.ErrCodeInvalidRequest, fmt.Errorf("index %d out of range, %d downloads available", req.Index, len(resp.Data.Downloads)))
+		return
+	}
+
+	media, err := s.config.Downloader.Open(r.Context(), resp.Data.Platform, resp.Data.Downloads[req.Index])
+	if err != nil {
+		writeError(w, videosdk.ErrorCodeOf(err), err)
+		return
+	}
+	defer media.Body.Close()
+
+	if contentType := media.Header.Get("Content-Type"); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	if media.ContentLength > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(media.ContentLength, 10))
+	}
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": fmt.Sprintf("%s_%s_%d", resp.Data.Platform, resp.Data.ID, req.Index+1),
+	}))
+	w.WriteHeader(http.StatusOK)
+
+	// 响应头已发送，复制失败时只能中断连接
+	_, _ = io.Copy(w, media.Body)
+}
+
+// handlePlatforms 返回支持的平台列表
+func (s *Server) handlePlatforms(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	platforms := s.sdk.GetSupportedPlatforms()
+	sort.Slice(platforms, func(i, j int) bool { return platforms[i] < platforms[j] })
+
+	writeJSON(w, http.StatusOK, &PlatformsResponse{Platforms: platforms})
+}
+
+// handleHealth 健康检查
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// authorized 校验API Key，支持X-API-Key请求头和Bearer Token
+func (s *Server) authorized(r *http.Request) bool {
+	if len(s.config.APIKeys) == 0 {
+		return true
+	}
+
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			key = strings.TrimPrefix(auth, "Bearer ")
+		}
+	}
+	if key == "" {
+		return false
+	}
+
+	for _, allowed := range s.config.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(allowed)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// applyCORS 设置CORS响应头，预检请求处理完成时返回true
+func (s *Server) applyCORS(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || len(s.config.AllowedOrigins) == 0 {
+		return false
+	}
+
+	allowed := false
+	for _, o := range s.config.AllowedOrigins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return false
+	}
+
+	h := w.Header()
+	h.Set("Access-Control-Allow-Origin", origin)
+	h.Add("Vary", "Origin")
+
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		h.Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
+		h.Set("Access-Control-Max-Age", "600")
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+
+	return false
+}
+
+// allowMethod 校验请求方法
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+
+	w.Header().Set("Allow", method)
+	writeJSON(w, http.StatusMethodNotAllowed, &videosdk.ParseResponse{
+		Success: false,
+		Code:    videosdk.ErrCodeInvalidRequest,
+		Error:   fmt.Sprintf("method %s not allowed", r.Method),
+		Time:    time.Now(),
+	})
+	return false
+}
+
+// decodeJSON 解析JSON请求体
+func decodeJSON(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return fmt.Errorf("request body too large, limit is %d bytes", maxErr.Limit)
+		}
+		return fmt.Errorf("invalid json body: %w", err)
+	}
+	return nil
+}
+
+// writeError 输出错误响应
+func writeError(w http.ResponseWriter, code videosdk.ErrorCode, err error) {
+	writeJSON(w, statusForCode(code), &videosdk.ParseResponse{
+		Success: false,
+		Code:    code,
+		Error:   err.Error(),
+		Time:    time.Now(),
+	})
+}
+
+// writeJSON 输出JSON响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// statusForCode 错误类别对应的HTTP状态码
+func statusForCode(code videosdk.ErrorCode) int {
+	switch code {
+	case "":
+		return http.StatusOK
+	case videosdk.ErrCodeInvalidRequest:
+		return http.StatusBadRequest
+	case videosdk.ErrCodeUnauthorized:
+		return http.StatusUnauthorized
+	case videosdk.ErrCodeTokenRequired:
+		return http.StatusForbidden
+	case videosdk.ErrCodeUnsupportedPlatform:
+		return http.StatusNotFound
+	case videosdk.ErrCodeRateLimited:
+		return http.StatusTooManyRequests
+	case videosdk.ErrCodeTimeout:
+		return http.StatusGatewayTimeout
+	case videosdk.ErrCodeCanceled, videosdk.ErrCodeBackendUnavailable:
+		return http.StatusServiceUnavailable
+	case videosdk.ErrCodeParseFailed, videosdk.ErrCodeDownloadFailed, videosdk.ErrCodeSchemaMismatch,
+		videosdk.ErrCodeLoginRequired, videosdk.ErrCodeVerificationRequired, videosdk.ErrCodeProxyFailed:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
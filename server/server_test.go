@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// errorSDK 只返回错误、响应为nil的SDK实现
+type errorSDK struct {
+	err error
+}
+
+func (s errorSDK) RegisterParser(videosdk.Parser) error       { return nil }
+func (s errorSDK) GetSupportedPlatforms() []videosdk.Platform { return nil }
+func (s errorSDK) SetTimeout(time.Duration)                   {}
+func (s errorSDK) SetUserAgent(string)                        {}
+
+func (s errorSDK) ParseVideo(context.Context, *videosdk.ParseRequest) (*videosdk.ParseResponse, error) {
+	return nil, s.err
+}
+
+func TestHandlersWithNilResponse(t *testing.T) {
+	srv := New(errorSDK{err: videosdk.NewError(videosdk.ErrCodeBackendUnavailable, context.DeadlineExceeded)}, Config{})
+
+	for _, path := range []string{"/parse", "/download"} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"platform":"douyin","video_id":"1"}`))
+		srv.ServeHTTP(rec, req)
+
+		var resp videosdk.ParseResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: decode response: %v (%s)", path, err, rec.Body)
+		}
+		if rec.Code != http.StatusGatewayTimeout || resp.Success || resp.Code != videosdk.ErrCodeTimeout || resp.Error == "" {
+			t.Errorf("%s: status %d, response %+v", path, rec.Code, resp)
+		}
+	}
+}
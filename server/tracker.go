@@ -0,0 +1,192 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/storage"
+	"github.com/caojianfei/parser/tracker"
+)
+
+// handleWatchlist 列出追踪列表或加入作品
+func (s *Server) handleWatchlist(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var req WatchRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeError(w, videosdk.ErrCodeInvalidRequest, err)
+			return
+		}
+		item := &storage.WatchItem{Platform: req.Platform, WorkID: req.WorkID, URL: req.URL}
+		if req.Interval != "" {
+			interval, err := time.ParseDuration(req.Interval)
+			if err != nil {
+				writeError(w, videosdk.ErrCodeInvalidRequest, fmt.Errorf("invalid interval: %q", req.Interval))
+				return
+			}
+			item.Interval = interval
+		}
+
+		item, err := s.config.Tracker.Watch(r.Context(), item)
+		if err != nil {
+			writeTrackerError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, item)
+
+	case http.MethodGet:
+		items, err := s.config.Tracker.Watchlist(r.Context())
+		if err != nil {
+			writeTrackerError(w, err)
+			return
+		}
+		if items == nil {
+			items = []*storage.WatchItem{}
+		}
+		writeJSON(w, http.StatusOK, &WatchlistResponse{Items: items})
+
+	default:
+		allowMethod(w, r, http.MethodGet+", "+http.MethodPost)
+	}
+}
+
+// handleWatchItem 将作品移出追踪列表
+func (s *Server) handleWatchItem(w http.ResponseWriter, r *http.Request) {
+	platform, id, ok := splitWorkPath(r.URL.Path, "/tracker/watchlist/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if !allowMethod(w, r, http.MethodDelete) {
+		return
+	}
+	if err := s.config.Tracker.Unwatch(r.Context(), platform, id); err != nil {
+		writeTrackerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleSeries 获取作品的统计时间序列
+func (s *Server) handleSeries(w http.ResponseWriter, r *http.Request) {
+	platform, id, ok := splitWorkPath(r.URL.Path, "/tracker/series/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	from, to, err := timeRange(r)
+	if err != nil {
+		writeError(w, videosdk.ErrCodeInvalidRequest, err)
+		return
+	}
+
+	format := tracker.Format(r.URL.Query().Get("format"))
+	if format != "" && format != tracker.FormatJSON && format != tracker.FormatCSV {
+		writeError(w, videosdk.ErrCodeInvalidRequest, fmt.Errorf("unsupported export format %q", format))
+		return
+	}
+
+	series, err := s.config.Tracker.Series(r.Context(), platform, id, from, to)
+	if err != nil {
+		writeTrackerError(w, err)
+		return
+	}
+	if format == tracker.FormatCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		_ = tracker.WriteCSV(w, series)
+		return
+	}
+	writeJSON(w, http.StatusOK, series)
+}
+
+// handleExport 导出追踪列表中全部作品的时间序列
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	from, to, err := timeRange(r)
+	if err != nil {
+		writeError(w, videosdk.ErrCodeInvalidRequest, err)
+		return
+	}
+
+	format := tracker.Format(r.URL.Query().Get("format"))
+	switch format {
+	case tracker.FormatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="stats.csv"`)
+	case tracker.FormatJSON, "":
+		w.Header().Set("Content-Type", "application/json")
+	default:
+		writeError(w, videosdk.ErrCodeInvalidRequest, fmt.Errorf("unsupported export format %q", format))
+		return
+	}
+
+	// Export读取完全部快照后才开始写出，存储错误发生在输出之前
+	if err := s.config.Tracker.Export(r.Context(), w, format, from, to); err != nil {
+		writeTrackerError(w, err)
+	}
+}
+
+// splitWorkPath 从"<prefix><platform>/<id>"中取出平台和作品ID
+func splitWorkPath(path, prefix string) (videosdk.Platform, string, bool) {
+	platform, id, ok := strings.Cut(strings.TrimPrefix(path, prefix), "/")
+	if !ok || platform == "" || id == "" || strings.Contains(id, "/") {
+		return "", "", false
+	}
+	return videosdk.Platform(platform), id, true
+}
+
+// timeRange 读取查询参数中的from和to，支持videosdk.ParseTime识别的格式
+func timeRange(r *http.Request) (from, to time.Time, err error) {
+	if from, err = timeParam(r, "from"); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if to, err = timeParam(r, "to"); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+// timeParam 读取时间查询参数，未设置时返回零值
+func timeParam(r *http.Request, name string) (time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t := videosdk.ParseTime(raw)
+	if t.IsZero() {
+		return time.Time{}, fmt.Errorf("invalid %s: %q", name, raw)
+	}
+	return t, nil
+}
+
+// writeTrackerError 输出统计追踪接口的错误响应
+func writeTrackerError(w http.ResponseWriter, err error) {
+	var sdkErr *videosdk.Error
+	if errors.As(err, &sdkErr) {
+		writeError(w, sdkErr.Code, err)
+		return
+	}
+
+	// 作品不在追踪列表中为请求错误，其余为存储错误
+	status := http.StatusInternalServerError
+	var code videosdk.ErrorCode
+	if errors.Is(err, storage.ErrNotFound) {
+		status, code = http.StatusNotFound, videosdk.ErrCodeInvalidRequest
+	}
+
+	writeJSON(w, status, &videosdk.ParseResponse{
+		Success: false,
+		Code:    code,
+		Error:   err.Error(),
+		Time:    time.Now(),
+	})
+}
@@ -0,0 +1,50 @@
+package videosdk
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// shareURLPattern 分享文案中的链接
+var shareURLPattern = regexp.MustCompile(`https?://[^\s"'<>，。！？、；）】]+`)
+
+// platformHosts 各平台的域名后缀
+var platformHosts = []struct {
+	suffix   string
+	platform Platform
+}{
+	{"douyin.com", PlatformDouyin},
+	{"iesdouyin.com", PlatformDouyin},
+	{"kuaishou.com", PlatformKuaishou},
+	{"chenzhongtech.com", PlatformKuaishou},
+	{"gifshow.com", PlatformKuaishou},
+	{"xiaohongshu.com", PlatformXiaohongshu},
+	{"xhslink.com", PlatformXiaohongshu},
+	{"bilibili.com", PlatformBilibili},
+	{"b23.tv", PlatformBilibili},
+	{"youtube.com", PlatformYoutube},
+	{"youtu.be", PlatformYoutube},
+}
+
+// ExtractURL 从分享文案中提取第一个链接，未找到时返回空字符串
+func ExtractURL(text string) string {
+	return shareURLPattern.FindString(text)
+}
+
+// DetectPlatform 根据链接域名识别平台，无法识别时返回空字符串
+func DetectPlatform(rawURL string) Platform {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, h := range platformHosts {
+		if host == h.suffix || strings.HasSuffix(host, "."+h.suffix) {
+			return h.platform
+		}
+	}
+
+	return ""
+}
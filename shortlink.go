@@ -0,0 +1,331 @@
+package videosdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultShortLinkHosts 默认识别的短链接域名
+var defaultShortLinkHosts = []string{"v.douyin.com", "v.kuaishou.com", "xhslink.com", "b23.tv", "t.cn"}
+
+// ShortLinkResolver 短链接解析器
+//
+// 直接请求短链接并跟随跳转，直到得到非短链接的地址，不经过后端服务。
+// 跳转次数有上限，出现循环跳转时返回错误；解析结果按短链接缓存。
+type ShortLinkResolver struct {
+	mu         sync.Mutex
+	client     *http.Client
+	userAgent  string
+	maxHops    int
+	ttl        time.Duration
+	maxEntries int
+	hosts      []string
+	endpoints  map[string]*url.URL
+	cache      map[string]shortLinkEntry
+}
+
+// shortLinkEntry 短链接解析结果缓存项
+type shortLinkEntry struct {
+	target  string
+	expires time.Time
+}
+
+// shortLinkProxyKey 请求上下文中代理地址的键
+type shortLinkProxyKey struct{}
+
+// NewShortLinkResolver 创建短链接解析器，默认最多跟随5次跳转，结果缓存1小时
+func NewShortLinkResolver() *ShortLinkResolver {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFromRequest
+
+	return &ShortLinkResolver{
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		userAgent:  "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
+		maxHops:    5,
+		ttl:        time.Hour,
+		maxEntries: 10000,
+		hosts:      append([]string(nil), defaultShortLinkHosts...),
+		endpoints:  make(map[string]*url.URL),
+		cache:      make(map[string]shortLinkEntry),
+	}
+}
+
+// proxyFromRequest 使用请求上下文中的代理地址
+func proxyFromRequest(req *http.Request) (*url.URL, error) {
+	proxy, _ := req.Context().Value(shortLinkProxyKey{}).(string)
+	if proxy == "" {
+		return nil, nil
+	}
+	return ParseProxyURL(proxy)
+}
+
+// SetMaxHops 设置最多跟随的跳转次数
+func (r *ShortLinkResolver) SetMaxHops(hops int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxHops = hops
+}
+
+// SetCache 设置解析结果的缓存时间和数量上限，ttl<=0时不缓存
+func (r *ShortLinkResolver) SetCache(ttl time.Duration, maxEntries int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ttl = ttl
+	r.maxEntries = maxEntries
+	r.cache = make(map[string]shortLinkEntry)
+}
+
+// SetTimeout 设置单次请求的超时时间
+func (r *ShortLinkResolver) SetTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// 进行中的请求可能仍在使用旧客户端，替换为新客户端而不是修改原客户端
+	client := *r.client
+	client.Timeout = timeout
+	r.client = &client
+}
+
+// SetUserAgent 设置请求短链接使用的User-Agent
+func (r *ShortLinkResolver) SetUserAgent(userAgent string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.userAgent = userAgent
+}
+
+// SetTransport 设置HTTP传输层，传输层需要自行处理代理
+func (r *ShortLinkResolver) SetTransport(transport http.RoundTripper) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	client := *r.client
+	client.Transport = transport
+	r.client = &client
+}
+
+// AddHost 添加需要解析的短链接域名
+func (r *ShortLinkResolver) AddHost(hosts ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, host := range hosts {
+		r.hosts = append(r.hosts, strings.ToLower(host))
+	}
+}
+
+// SetEndpoint 将某个短链接域名的请求发往指定地址（如本地测试服务），Host请求头保持原域名
+func (r *ShortLinkResolver) SetEndpoint(host, baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid endpoint: %q", baseURL)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.endpoints[strings.ToLower(host)] = u
+	return nil
+}
+
+// IsShortLink 判断链接是否为需要解析的短链接
+func (r *ShortLinkResolver) IsShortLink(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return matchHost(strings.ToLower(u.Hostname()), r.hosts...)
+}
+
+// Resolve 解析短链接，返回跳转后的第一个非短链接地址（保留其查询参数）
+//
+// rawURL可以是分享文案；proxy非空时通过该代理请求。不是短链接时原样返回提取出的链接。
+func (r *ShortLinkResolver) Resolve(ctx context.Context, rawURL string, proxy string) (target string, err error) {
+	link := strings.TrimSpace(rawURL)
+	if extracted := ExtractURL(link); extracted != "" {
+		link = extracted
+	}
+	if !r.IsShortLink(link) {
+		return link, nil
+	}
+
+	u, _ := url.Parse(link)
+	u.Scheme = "https"
+	u.Host = strings.ToLower(u.Host)
+	u.RawQuery = ""
+	u.Fragment = ""
+	key := u.Host + "/" + strings.Trim(u.EscapedPath(), "/")
+
+	if target, ok := r.cached(key); ok {
+		return target, nil
+	}
+
+	ctx, span := StartSpan(ctx, "ShortLinkResolver.Resolve", Attribute{Key: "host", Value: u.Host})
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	r.mu.Lock()
+	maxHops := r.maxHops
+	r.mu.Unlock()
+
+	ctx = context.WithValue(ctx, shortLinkProxyKey{}, proxy)
+	current := u.String()
+	visited := map[string]bool{current: true}
+	for hop := 1; hop <= maxHops; hop++ {
+		next, err := r.follow(ctx, current, proxy)
+		if err != nil {
+			return "", err
+		}
+		if visited[next] {
+			return "", NewError(ErrCodeParseFailed, fmt.Errorf("short link %s redirects in a loop at %s", RedactURL(link), RedactURL(next)))
+		}
+		visited[next] = true
+
+		if !r.IsShortLink(next) {
+			span.SetAttributes(Attribute{Key: "hops", Value: fmt.Sprint(hop)})
+			LoggerFromContext(ctx).DebugContext(ctx, "short link resolved",
+				slog.String("short_url", RedactURL(link)),
+				slog.String("url", RedactURL(next)),
+				slog.Int("hops", hop),
+			)
+			r.store(key, next)
+			return next, nil
+		}
+		current = next
+	}
+
+	return "", NewError(ErrCodeParseFailed, fmt.Errorf("short link %s exceeded %d redirects", RedactURL(link), maxHops))
+}
+
+// follow 请求一次链接，返回跳转地址
+func (r *ShortLinkResolver) follow(ctx context.Context, current string, proxy string) (string, error) {
+	target, err := url.Parse(current)
+	if err != nil {
+		return "", NewError(ErrCodeParseFailed, fmt.Errorf("invalid redirect url: %w", err))
+	}
+
+	r.mu.Lock()
+	client := r.client
+	userAgent := r.userAgent
+	endpoint := r.endpoints[target.Hostname()]
+	r.mu.Unlock()
+
+	requestURL := *target
+	if endpoint != nil {
+		requestURL.Scheme = endpoint.Scheme
+		requestURL.Host = endpoint.Host
+		requestURL.Path = strings.TrimSuffix(endpoint.Path, "/") + target.Path
+		requestURL.RawPath = ""
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return "", NewError(ErrCodeParseFailed, fmt.Errorf("create short link request: %w", err))
+	}
+	req.Host = target.Host
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if proxy != "" && ctx.Err() == nil {
+			ReportProxyError(ctx, proxy, err)
+			return "", NewError(ErrCodeProxyFailed, fmt.Errorf("request short link %s: %w", RedactURL(current), err))
+		}
+		return "", fmt.Errorf("request short link %s: %w", RedactURL(current), err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		location := resp.Header.Get("Location")
+		if location == "" {
+			return "", NewError(ErrCodeParseFailed, fmt.Errorf("short link %s returned %d without location", RedactURL(current), resp.StatusCode))
+		}
+		next, err := target.Parse(location)
+		if err != nil {
+			return "", NewError(ErrCodeParseFailed, fmt.Errorf("invalid redirect location %q: %w", location, err))
+		}
+		return next.String(), nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return "", NewError(ErrCodeInvalidRequest, fmt.Errorf("short link %s not found (%d)", RedactURL(current), resp.StatusCode))
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "", NewError(ErrCodeRateLimited, fmt.Errorf("short link %s rate limited", RedactURL(current)))
+	default:
+		return "", NewError(ErrCodeParseFailed, fmt.Errorf("short link %s did not redirect (%d)", RedactURL(current), resp.StatusCode))
+	}
+}
+
+// cached 获取未过期的解析结果
+func (r *ShortLinkResolver) cached(key string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.cache[key]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(entry.expires) {
+		delete(r.cache, key)
+		return "", false
+	}
+	return entry.target, true
+}
+
+// store 缓存解析结果，超出数量上限时先清理过期结果，仍然超出时清空缓存
+func (r *ShortLinkResolver) store(key, target string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ttl <= 0 {
+		return
+	}
+	if r.maxEntries > 0 && len(r.cache) >= r.maxEntries {
+		now := time.Now()
+		for k, entry := range r.cache {
+			if now.After(entry.expires) {
+				delete(r.cache, k)
+			}
+		}
+		if len(r.cache) >= r.maxEntries {
+			r.cache = make(map[string]shortLinkEntry)
+		}
+	}
+	r.cache[key] = shortLinkEntry{target: target, expires: time.Now().Add(r.ttl)}
+}
+
+// shortLinkResolverKey 上下文中短链接解析器的键
+type shortLinkResolverKey struct{}
+
+// withShortLinkResolver 将短链接解析器放入上下文，供解析器在提取作品ID前解析短链接
+func withShortLinkResolver(ctx context.Context, r *ShortLinkResolver) context.Context {
+	if r == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, shortLinkResolverKey{}, r)
+}
+
+// ResolveShortLink 使用上下文中的短链接解析器解析短链接
+//
+// 上下文中没有解析器（未通过SDK调用或SDK禁用了短链接解析）或链接不是短链接时原样返回rawURL，
+// 解析器可以据此回退到后端的短链接解析接口。
+func ResolveShortLink(ctx context.Context, rawURL string, proxy string) (string, error) {
+	r, ok := ctx.Value(shortLinkResolverKey{}).(*ShortLinkResolver)
+	if !ok || !r.IsShortLink(ExtractURL(rawURL)) {
+		return rawURL, nil
+	}
+	return r.Resolve(ctx, rawURL, proxy)
+}
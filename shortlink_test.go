@@ -0,0 +1,123 @@
+package videosdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newShortLinkServer 启动按路径跳转的短链接服务，返回指向它的解析器和请求计数
+func newShortLinkServer(t *testing.T) (*ShortLinkResolver, *int32) {
+	t.Helper()
+
+	redirects := map[string]string{
+		"/chain":  "https://v.douyin.com/chain2",
+		"/chain2": "https://www.douyin.com/video/7412345678901234567?previous_page=app_code_link",
+		"/loop1":  "https://v.douyin.com/loop2",
+		"/loop2":  "https://v.douyin.com/loop1",
+		"/hop1":   "https://v.douyin.com/hop2",
+		"/hop2":   "https://v.douyin.com/hop3",
+		"/hop3":   "https://www.douyin.com/video/7412345678901234567",
+	}
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Host != "v.douyin.com" {
+			t.Errorf("host = %q, want v.douyin.com", r.Host)
+		}
+		location, ok := redirects[strings.TrimSuffix(r.URL.Path, "/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.Redirect(w, r, location, http.StatusFound)
+	}))
+	t.Cleanup(server.Close)
+
+	resolver := NewShortLinkResolver()
+	if err := resolver.SetEndpoint("v.douyin.com", server.URL); err != nil {
+		t.Fatal(err)
+	}
+	return resolver, &requests
+}
+
+func TestShortLinkResolver(t *testing.T) {
+	tests := []struct {
+		link    string
+		want    string
+		code    ErrorCode
+		message string
+	}{
+		{"复制打开抖音 https://v.douyin.com/chain/ 看看", "https://www.douyin.com/video/7412345678901234567?previous_page=app_code_link", "", ""},
+		{"https://v.douyin.com/loop1", "", ErrCodeParseFailed, "loop"},
+		{"https://v.douyin.com/hop1", "", ErrCodeParseFailed, "exceeded 2 redirects"},
+		{"https://v.douyin.com/missing", "", ErrCodeInvalidRequest, "not found"},
+		{"https://www.douyin.com/video/1", "https://www.douyin.com/video/1", "", ""},
+	}
+
+	resolver, _ := newShortLinkServer(t)
+	resolver.SetMaxHops(2)
+	for _, tt := range tests {
+		got, err := resolver.Resolve(context.Background(), tt.link, "")
+		if code := ErrorCodeOf(err); code != tt.code || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q, code %q", tt.link, got, err, tt.want, tt.code)
+			continue
+		}
+		if tt.message != "" && !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("Resolve(%q) error = %v, want containing %q", tt.link, err, tt.message)
+		}
+	}
+}
+
+func TestShortLinkResolverCache(t *testing.T) {
+	resolver, requests := newShortLinkServer(t)
+
+	for i := 0; i < 3; i++ {
+		// 查询参数和大小写不同的同一短链接共享缓存
+		link := "https://v.douyin.com/chain"
+		if i == 1 {
+			link = "https://V.DOUYIN.COM/chain?utm_source=copy"
+		}
+		if _, err := resolver.Resolve(context.Background(), link, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("requests = %d, want 2 (one resolution of two hops)", n)
+	}
+
+	resolver.SetCache(0, 0)
+	if _, err := resolver.Resolve(context.Background(), "https://v.douyin.com/chain", ""); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(requests); n != 4 {
+		t.Errorf("requests after disabling cache = %d, want 4", n)
+	}
+}
+
+func TestShortLinkResolverConcurrentConfig(t *testing.T) {
+	resolver, _ := newShortLinkServer(t)
+	resolver.SetCache(0, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := resolver.Resolve(context.Background(), "https://v.douyin.com/chain", ""); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			resolver.SetTimeout(5 * time.Second)
+			resolver.SetTransport(http.DefaultTransport)
+		}()
+	}
+	wg.Wait()
+}
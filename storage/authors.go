@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// SaveAuthor 保存作者，按平台+用户ID新增或更新
+//
+// 本次为空的字段（如作品详情中没有的签名、粉丝数）保留已归档的值。
+func (s *Store) SaveAuthor(ctx context.Context, platform videosdk.Platform, author *videosdk.AuthorInfo) error {
+	return saveAuthor(ctx, s.db, platform, author, s.now())
+}
+
+// saveAuthor 新增或更新作者
+func saveAuthor(ctx context.Context, q queryer, platform videosdk.Platform, author *videosdk.AuthorInfo, now time.Time) error {
+	if platform == "" || author.UID == "" {
+		return fmt.Errorf("save author: platform and uid are required")
+	}
+
+	if _, err := q.ExecContext(ctx, `
+		INSERT INTO authors (platform, uid, sec_uid, unique_id, nickname, avatar, signature, age, follower_count, first_seen, last_seen)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (platform, uid) DO UPDATE SET
+			sec_uid        = COALESCE(NULLIF(excluded.sec_uid, ''), authors.sec_uid),
+			unique_id      = COALESCE(NULLIF(excluded.unique_id, ''), authors.unique_id),
+			nickname       = COALESCE(NULLIF(excluded.nickname, ''), authors.nickname),
+			avatar         = COALESCE(NULLIF(excluded.avatar, ''), authors.avatar),
+			signature      = COALESCE(NULLIF(excluded.signature, ''), authors.signature),
+			age            = CASE WHEN excluded.age > 0 THEN excluded.age ELSE authors.age END,
+			follower_count = COALESCE(excluded.follower_count, authors.follower_count),
+			last_seen      = excluded.last_seen`,
+		string(platform), author.UID, author.SecUID, author.UniqueID, author.Nickname, author.Avatar,
+		author.Signature, author.Age, countValue(author.FollowerCount), now.UnixNano(), now.UnixNano(),
+	); err != nil {
+		return fmt.Errorf("save author %s/%s: %w", platform, author.UID, err)
+	}
+	return nil
+}
+
+// GetAuthor 获取作者，不存在时返回ErrNotFound
+func (s *Store) GetAuthor(ctx context.Context, platform videosdk.Platform, uid string) (*Author, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+authorColumns+` FROM authors WHERE platform = ? AND uid = ?`,
+		string(platform), uid,
+	)
+	author, err := scanAuthor(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get author %s/%s: %w", platform, uid, err)
+	}
+	return author, nil
+}
+
+// Authors 列出作者，按最近保存时间倒序排列，platform为空时列出全部平台
+func (s *Store) Authors(ctx context.Context, platform videosdk.Platform) ([]*Author, error) {
+	query := `SELECT ` + authorColumns + ` FROM authors`
+	var args []interface{}
+	if platform != "" {
+		query += ` WHERE platform = ?`
+		args = append(args, string(platform))
+	}
+	query += ` ORDER BY last_seen DESC, platform, uid`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list authors: %w", err)
+	}
+	defer rows.Close()
+
+	var authors []*Author
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list authors: %w", err)
+		}
+		authors = append(authors, author)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list authors: %w", err)
+	}
+	return authors, nil
+}
+
+// authorColumns scanAuthor读取的列
+const authorColumns = `platform, uid, sec_uid, unique_id, nickname, avatar, signature, age, follower_count, first_seen, last_seen`
+
+// scanAuthor 读取作者行
+func scanAuthor(row scanner) (*Author, error) {
+	var (
+		author              Author
+		platform            string
+		followerCount       sql.NullInt64
+		firstSeen, lastSeen sql.NullInt64
+	)
+	if err := row.Scan(
+		&platform, &author.UID, &author.SecUID, &author.UniqueID, &author.Nickname, &author.Avatar,
+		&author.Signature, &author.Age, &followerCount, &firstSeen, &lastSeen,
+	); err != nil {
+		return nil, err
+	}
+	author.Platform = videosdk.Platform(platform)
+	author.FollowerCount = scanCount(followerCount)
+	author.FirstSeen = scanTime(firstSeen)
+	author.LastSeen = scanTime(lastSeen)
+	return &author, nil
+}
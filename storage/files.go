@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// AddFiles 记录作品已下载的文件，路径会转换为绝对路径，重复记录时更新文件大小
+//
+// 通常与Downloader.Download一起使用：
+//
+//	paths, err := downloader.Download(ctx, info, dir)
+//	_ = store.AddFiles(ctx, info.Platform, info.ID, paths...)
+func (s *Store) AddFiles(ctx context.Context, platform videosdk.Platform, workID string, paths ...string) error {
+	now := s.now()
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		for _, path := range paths {
+			if abs, err := filepath.Abs(path); err == nil {
+				path = abs
+			}
+			var size int64
+			if stat, err := os.Stat(path); err == nil {
+				size = stat.Size()
+			}
+
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO files (platform, work_id, path, size, downloaded_at) VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (platform, work_id, path) DO UPDATE SET
+					size          = excluded.size,
+					downloaded_at = excluded.downloaded_at`,
+				string(platform), workID, path, size, now.UnixNano(),
+			); err != nil {
+				return fmt.Errorf("save file %s/%s: %w", platform, workID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Files 查询作品已下载的文件，按路径排列
+func (s *Store) Files(ctx context.Context, platform videosdk.Platform, workID string) ([]File, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT path, size, downloaded_at FROM files WHERE platform = ? AND work_id = ? ORDER BY path`,
+		string(platform), workID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list files %s/%s: %w", platform, workID, err)
+	}
+	defer rows.Close()
+
+	var files []File
+	for rows.Next() {
+		file := File{Platform: platform, WorkID: workID}
+		var downloadedAt sql.NullInt64
+		if err := rows.Scan(&file.Path, &file.Size, &downloadedAt); err != nil {
+			return nil, fmt.Errorf("list files %s/%s: %w", platform, workID, err)
+		}
+		file.DownloadedAt = scanTime(downloadedAt)
+		files = append(files, file)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list files %s/%s: %w", platform, workID, err)
+	}
+	return files, nil
+}
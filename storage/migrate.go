@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migrations 数据库迁移，按顺序执行，版本号为下标+1
+//
+// 已发布的迁移不能修改，结构变更需要追加新的迁移。
+var migrations = []string{
+	// 1: 初始结构
+	`
+	CREATE TABLE authors (
+		platform       TEXT NOT NULL,
+		uid            TEXT NOT NULL,
+		sec_uid        TEXT NOT NULL DEFAULT '',
+		unique_id      TEXT NOT NULL DEFAULT '',
+		nickname       TEXT NOT NULL DEFAULT '',
+		avatar         TEXT NOT NULL DEFAULT '',
+		signature      TEXT NOT NULL DEFAULT '',
+		age            INTEGER NOT NULL DEFAULT 0,
+		follower_count INTEGER,
+		first_seen     INTEGER NOT NULL,
+		last_seen      INTEGER NOT NULL,
+		PRIMARY KEY (platform, uid)
+	);
+
+	CREATE TABLE works (
+		platform    TEXT NOT NULL,
+		id          TEXT NOT NULL,
+		author_uid  TEXT NOT NULL DEFAULT '',
+		title       TEXT NOT NULL DEFAULT '',
+		type        TEXT NOT NULL DEFAULT '',
+		create_time INTEGER,
+		data        TEXT NOT NULL,
+		first_seen  INTEGER NOT NULL,
+		last_seen   INTEGER NOT NULL,
+		PRIMARY KEY (platform, id)
+	);
+	CREATE INDEX works_author ON works (platform, author_uid, create_time);
+	CREATE INDEX works_create_time ON works (create_time);
+
+	CREATE TABLE work_tags (
+		platform TEXT NOT NULL,
+		work_id  TEXT NOT NULL,
+		tag      TEXT NOT NULL,
+		PRIMARY KEY (platform, work_id, tag)
+	);
+	CREATE INDEX work_tags_tag ON work_tags (tag);
+
+	CREATE TABLE stats_snapshots (
+		platform      TEXT NOT NULL,
+		work_id       TEXT NOT NULL,
+		captured_at   INTEGER NOT NULL,
+		play_count    INTEGER,
+		like_count    INTEGER,
+		comment_count INTEGER,
+		share_count   INTEGER,
+		collect_count INTEGER,
+		PRIMARY KEY (platform, work_id, captured_at)
+	);
+
+	CREATE TABLE files (
+		platform      TEXT NOT NULL,
+		work_id       TEXT NOT NULL,
+		path          TEXT NOT NULL,
+		size          INTEGER NOT NULL DEFAULT 0,
+		downloaded_at INTEGER NOT NULL,
+		PRIMARY KEY (platform, work_id, path)
+	);
+	`,
+	// 2: 统计追踪列表
+	`
+	CREATE TABLE watchlist (
+		platform   TEXT NOT NULL,
+		work_id    TEXT NOT NULL,
+		url        TEXT NOT NULL DEFAULT '',
+		interval   INTEGER NOT NULL DEFAULT 0,
+		added_at   INTEGER NOT NULL,
+		last_run   INTEGER,
+		next_run   INTEGER NOT NULL,
+		last_error TEXT NOT NULL DEFAULT '',
+		failures   INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (platform, work_id)
+	);
+	CREATE INDEX watchlist_next_run ON watchlist (next_run);
+	`,
+}
+
+// migrate 执行尚未应用的迁移，每个迁移在单独的事务中执行
+func migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			applied_at INTEGER NOT NULL
+		)`); err != nil {
+		return fmt.Errorf("init migrations: %w", err)
+	}
+
+	var current int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if current > len(migrations) {
+		return fmt.Errorf("archive schema version %d is newer than supported version %d", current, len(migrations))
+	}
+
+	for version := current + 1; version <= len(migrations); version++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("migrate to version %d: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, migrations[version-1]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrate to version %d: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+			version, time.Now().UnixNano(),
+		); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrate to version %d: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migrate to version %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// SchemaVersion 当前数据库结构版本
+func (s *Store) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// AddSnapshot 记录作品在at时刻的统计数据，同一时刻重复记录时覆盖
+func (s *Store) AddSnapshot(ctx context.Context, platform videosdk.Platform, workID string, stats videosdk.VideoStats, at time.Time) error {
+	if at.IsZero() {
+		at = s.now()
+	}
+	return addSnapshot(ctx, s.db, platform, workID, stats, at)
+}
+
+// addSnapshot 写入统计快照
+func addSnapshot(ctx context.Context, q queryer, platform videosdk.Platform, workID string, stats videosdk.VideoStats, at time.Time) error {
+	if _, err := q.ExecContext(ctx, `
+		INSERT OR REPLACE INTO stats_snapshots
+			(platform, work_id, captured_at, play_count, like_count, comment_count, share_count, collect_count)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		string(platform), workID, at.UnixNano(),
+		countValue(stats.PlayCount), countValue(stats.LikeCount), countValue(stats.CommentCount),
+		countValue(stats.ShareCount), countValue(stats.CollectCount),
+	); err != nil {
+		return fmt.Errorf("save stats snapshot %s/%s: %w", platform, workID, err)
+	}
+	return nil
+}
+
+// Snapshots 查询作品在[from, to)内的统计快照，按采集时间升序排列，from或to为零值时不限制
+func (s *Store) Snapshots(ctx context.Context, platform videosdk.Platform, workID string, from, to time.Time) ([]Snapshot, error) {
+	query := `SELECT ` + snapshotColumns + ` FROM stats_snapshots WHERE platform = ? AND work_id = ?`
+	args := []interface{}{string(platform), workID}
+	if !from.IsZero() {
+		query += ` AND captured_at >= ?`
+		args = append(args, from.UnixNano())
+	}
+	if !to.IsZero() {
+		query += ` AND captured_at < ?`
+		args = append(args, to.UnixNano())
+	}
+	query += ` ORDER BY captured_at`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list stats snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []Snapshot
+	for rows.Next() {
+		snapshot, err := scanSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list stats snapshots: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list stats snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// LatestSnapshot 获取作品最近一次的统计快照，没有快照时返回ErrNotFound
+func (s *Store) LatestSnapshot(ctx context.Context, platform videosdk.Platform, workID string) (Snapshot, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+snapshotColumns+` FROM stats_snapshots WHERE platform = ? AND work_id = ? ORDER BY captured_at DESC LIMIT 1`,
+		string(platform), workID,
+	)
+	snapshot, err := scanSnapshot(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Snapshot{}, ErrNotFound
+	}
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("get stats snapshot %s/%s: %w", platform, workID, err)
+	}
+	return snapshot, nil
+}
+
+// snapshotColumns scanSnapshot读取的列
+const snapshotColumns = `platform, work_id, captured_at, play_count, like_count, comment_count, share_count, collect_count`
+
+// scanSnapshot 读取统计快照行
+func scanSnapshot(row scanner) (Snapshot, error) {
+	var (
+		snapshot                            Snapshot
+		platform                            string
+		capturedAt                          int64
+		play, like, comment, share, collect sql.NullInt64
+	)
+	if err := row.Scan(&platform, &snapshot.WorkID, &capturedAt, &play, &like, &comment, &share, &collect); err != nil {
+		return Snapshot{}, err
+	}
+	snapshot.Platform = videosdk.Platform(platform)
+	snapshot.CapturedAt = time.Unix(0, capturedAt)
+	snapshot.Stats = videosdk.VideoStats{
+		PlayCount:    scanCount(play),
+		LikeCount:    scanCount(like),
+		CommentCount: scanCount(comment),
+		ShareCount:   scanCount(share),
+		CollectCount: scanCount(collect),
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,169 @@
+// Package storage 将解析结果持久化到本地SQLite数据库，作为本地作品归档的核心
+//
+// Store保存作品信息（VideoInfo）、作者信息（AuthorInfo）、带时间戳的统计快照以及已下载文件的路径，
+// 作品和作者按平台+ID去重，重复保存时更新为最新数据。数据库结构通过内置的迁移自动创建和升级：
+//
+//	store, _ := storage.Open("archive.db")
+//	defer store.Close()
+//
+//	// 解析成功后自动归档
+//	sdk.Use(store.Middleware())
+//
+//	works, _ := store.WorksByAuthor(ctx, videosdk.PlatformKuaishou, "3xauthor")
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+
+	_ "modernc.org/sqlite" // 纯Go实现的SQLite驱动
+)
+
+// ErrNotFound 作品或作者不存在
+var ErrNotFound = errors.New("not found")
+
+// Work 归档的作品
+type Work struct {
+	videosdk.VideoInfo
+
+	FirstSeen time.Time `json:"first_seen"` // 首次归档时间
+	LastSeen  time.Time `json:"last_seen"`  // 最近一次保存时间
+}
+
+// Author 归档的作者
+type Author struct {
+	videosdk.AuthorInfo
+
+	Platform  videosdk.Platform `json:"platform"`   // 平台
+	FirstSeen time.Time         `json:"first_seen"` // 首次归档时间
+	LastSeen  time.Time         `json:"last_seen"`  // 最近一次保存时间
+}
+
+// Snapshot 某一时刻的作品统计数据
+type Snapshot struct {
+	Platform   videosdk.Platform   `json:"platform"`    // 平台
+	WorkID     string              `json:"work_id"`     // 作品ID
+	CapturedAt time.Time           `json:"captured_at"` // 采集时间
+	Stats      videosdk.VideoStats `json:"stats"`       // 统计数据
+}
+
+// File 已下载的作品文件
+type File struct {
+	Platform     videosdk.Platform `json:"platform"`      // 平台
+	WorkID       string            `json:"work_id"`       // 作品ID
+	Path         string            `json:"path"`          // 文件路径
+	Size         int64             `json:"size"`          // 文件大小（字节，保存时文件不存在则为0）
+	DownloadedAt time.Time         `json:"downloaded_at"` // 保存时间
+}
+
+// Store SQLite归档存储，可以被多个协程同时使用
+type Store struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// Open 打开或创建归档数据库，并执行尚未应用的迁移
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	// SQLite同一时间只允许一个写入者，使用单连接避免锁冲突
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(context.Background(), db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db, now: time.Now}, nil
+}
+
+// Close 关闭数据库
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// DB 底层数据库连接，用于自定义查询
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// skipArchiveKey 跳过自动归档的context键
+type skipArchiveKey struct{}
+
+// SkipArchive 返回不被Middleware自动归档的ctx，用于调用方自行保存解析结果的场景
+func SkipArchive(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipArchiveKey{}, true)
+}
+
+// Middleware 返回解析成功后自动归档作品的中间件
+//
+// 归档失败只记录日志，不影响解析结果。ctx由SkipArchive生成时不归档。
+func (s *Store) Middleware() videosdk.Middleware {
+	return videosdk.AfterParse(func(ctx context.Context, req *videosdk.ParseRequest, info *videosdk.VideoInfo) error {
+		if skip, _ := ctx.Value(skipArchiveKey{}).(bool); skip {
+			return nil
+		}
+		if err := s.SaveWork(ctx, info); err != nil {
+			videosdk.LoggerFromContext(ctx).Warn("archive work failed",
+				"platform", info.Platform,
+				"id", info.ID,
+				"error", err,
+			)
+		}
+		return nil
+	})
+}
+
+// withTx 在事务中执行fn，fn返回错误时回滚
+func (s *Store) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// timeValue 时间的存储值（Unix纳秒），零值保存为NULL
+func timeValue(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UnixNano()
+}
+
+// scanTime 读取timeValue保存的时间
+func scanTime(value sql.NullInt64) time.Time {
+	if !value.Valid {
+		return time.Time{}
+	}
+	return time.Unix(0, value.Int64)
+}
+
+// countValue 数量的存储值，未知数量保存为NULL
+func countValue(count videosdk.Count) interface{} {
+	if count < 0 {
+		return nil
+	}
+	return int64(count)
+}
+
+// scanCount 读取countValue保存的数量
+func scanCount(value sql.NullInt64) videosdk.Count {
+	if !value.Valid {
+		return videosdk.CountUnknown
+	}
+	return videosdk.Count(value.Int64)
+}
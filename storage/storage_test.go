@@ -0,0 +1,319 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// openTestStore 在临时目录中打开归档，now依次返回base之后的每一秒
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "archive.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	clock := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	return store
+}
+
+// testWork 构造统计数据全部已知的作品
+func testWork(platform videosdk.Platform, id, uid string, created time.Time, tags ...string) *videosdk.VideoInfo {
+	return &videosdk.VideoInfo{
+		ID:         id,
+		Title:      "作品" + id,
+		Type:       videosdk.VideoTypeVideo,
+		Platform:   platform,
+		CreateTime: created,
+		Author:     videosdk.AuthorInfo{UID: uid, Nickname: "作者" + uid, FollowerCount: 100},
+		Stats:      videosdk.VideoStats{PlayCount: 10, LikeCount: 5, CommentCount: 2, ShareCount: 1, CollectCount: 0},
+		Tags:       tags,
+	}
+}
+
+// workIDs 提取作品ID，便于比较查询结果
+func workIDs(works []*Work) []string {
+	ids := make([]string, 0, len(works))
+	for _, work := range works {
+		ids = append(ids, work.ID)
+	}
+	return ids
+}
+
+func TestMigrations(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "archive.db")
+
+	// 模拟只应用了第一个迁移的旧数据库
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrate(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`DROP TABLE watchlist; DELETE FROM schema_migrations WHERE version > 1`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	for i := 0; i < 2; i++ {
+		store, err := Open(path)
+		if err != nil {
+			t.Fatalf("open #%d: %v", i+1, err)
+		}
+		version, err := store.SchemaVersion(ctx)
+		if err != nil || version != len(migrations) {
+			t.Errorf("open #%d: schema version = %d, %v, want %d", i+1, version, err, len(migrations))
+		}
+		var applied int
+		if err := store.DB().QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&applied); err != nil || applied != len(migrations) {
+			t.Errorf("open #%d: applied migrations = %d, %v", i+1, applied, err)
+		}
+		if _, err := store.WatchItems(ctx); err != nil {
+			t.Errorf("open #%d: watchlist not migrated: %v", i+1, err)
+		}
+		store.Close()
+	}
+
+	// 更新版本的数据库不能被旧代码打开
+	db, err = sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, 0)`, len(migrations)+1); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	if store, err := Open(path); err == nil {
+		store.Close()
+		t.Error("open newer schema: want error")
+	}
+}
+
+func TestSaveWorkUpsert(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	if err := store.SaveWork(ctx, testWork(videosdk.PlatformDouyin, "1", "u1", time.Time{})); err != nil {
+		t.Fatal(err)
+	}
+	first, err := store.GetWork(ctx, videosdk.PlatformDouyin, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同一平台+ID更新为最新数据，其它平台的同ID作品单独保存
+	updated := testWork(videosdk.PlatformDouyin, "1", "u1", time.Time{})
+	updated.Title = "新标题"
+	if err := store.SaveWorks(ctx, []*videosdk.VideoInfo{updated, nil, testWork(videosdk.PlatformKuaishou, "1", "u1", time.Time{})}); err != nil {
+		t.Fatal(err)
+	}
+
+	work, err := store.GetWork(ctx, videosdk.PlatformDouyin, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if work.Title != "新标题" {
+		t.Errorf("title = %q, want updated title", work.Title)
+	}
+	if !work.FirstSeen.Equal(first.FirstSeen) || !work.LastSeen.After(first.LastSeen) {
+		t.Errorf("first_seen %v -> %v, last_seen %v -> %v", first.FirstSeen, work.FirstSeen, first.LastSeen, work.LastSeen)
+	}
+
+	var works, snapshots int
+	store.DB().QueryRow(`SELECT COUNT(*) FROM works`).Scan(&works)
+	store.DB().QueryRow(`SELECT COUNT(*) FROM stats_snapshots WHERE platform = 'douyin'`).Scan(&snapshots)
+	if works != 2 || snapshots != 2 {
+		t.Errorf("works = %d, douyin snapshots = %d, want 2 and 2", works, snapshots)
+	}
+
+	if err := store.SaveWork(ctx, &videosdk.VideoInfo{ID: "1"}); err == nil {
+		t.Error("save work without platform: want error")
+	}
+	if _, err := store.GetWork(ctx, videosdk.PlatformXiaohongshu, "1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("get missing work: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSaveWorkReplacesTags(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	if err := store.SaveWork(ctx, testWork(videosdk.PlatformDouyin, "1", "u1", time.Time{}, "旅行", "美食", " ")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveWork(ctx, testWork(videosdk.PlatformDouyin, "1", "u1", time.Time{}, " 美食 ", "日常", "日常")); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := store.DB().Query(`SELECT tag FROM work_tags WHERE platform = 'douyin' AND work_id = '1'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var tags []string
+	for rows.Next() {
+		var tag string
+		rows.Scan(&tag)
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	if want := []string{"日常", "美食"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %q, want %q", tags, want)
+	}
+
+	if works, err := store.WorksByTag(ctx, "旅行"); err != nil || len(works) != 0 {
+		t.Errorf("works by removed tag = %v, %v", workIDs(works), err)
+	}
+	if works, err := store.WorksByTag(ctx, " 日常"); err != nil || len(works) != 1 {
+		t.Errorf("works by new tag = %v, %v", workIDs(works), err)
+	}
+}
+
+func TestUnknownCounts(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	info := testWork(videosdk.PlatformDouyin, "1", "u1", time.Time{})
+	info.Stats = videosdk.VideoStats{
+		PlayCount:    videosdk.CountUnknown,
+		LikeCount:    0,
+		CommentCount: 7,
+		ShareCount:   videosdk.CountUnknown,
+		CollectCount: videosdk.CountUnknown,
+	}
+	if err := store.SaveWork(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+
+	// 未知数量保存为NULL，0保存为0
+	var play, like sql.NullInt64
+	if err := store.DB().QueryRow(`SELECT play_count, like_count FROM stats_snapshots`).Scan(&play, &like); err != nil {
+		t.Fatal(err)
+	}
+	if play.Valid || !like.Valid || like.Int64 != 0 {
+		t.Errorf("play_count = %+v, like_count = %+v, want NULL and 0", play, like)
+	}
+
+	snapshot, err := store.LatestSnapshot(ctx, videosdk.PlatformDouyin, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Stats != info.Stats {
+		t.Errorf("snapshot stats = %+v, want %+v", snapshot.Stats, info.Stats)
+	}
+
+	// 作者粉丝数未知时保留已归档的值
+	info.Author.FollowerCount = videosdk.CountUnknown
+	if err := store.SaveWork(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+	author, err := store.GetAuthor(ctx, videosdk.PlatformDouyin, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if author.FollowerCount != 100 {
+		t.Errorf("follower_count = %v, want archived 100", author.FollowerCount)
+	}
+
+	if err := store.SaveAuthor(ctx, videosdk.PlatformKuaishou, &videosdk.AuthorInfo{UID: "u2", FollowerCount: videosdk.CountUnknown}); err != nil {
+		t.Fatal(err)
+	}
+	if author, err := store.GetAuthor(ctx, videosdk.PlatformKuaishou, "u2"); err != nil || author.FollowerCount != videosdk.CountUnknown {
+		t.Errorf("unknown follower_count = %v, %v, want CountUnknown", author, err)
+	}
+}
+
+func TestFindWorks(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	day := func(d int) time.Time { return time.Date(2024, 9, d, 12, 0, 0, 0, time.UTC) }
+	if err := store.SaveWorks(ctx, []*videosdk.VideoInfo{
+		testWork(videosdk.PlatformDouyin, "d1", "a", day(1), "旅行"),
+		testWork(videosdk.PlatformDouyin, "d2", "a", day(3)),
+		testWork(videosdk.PlatformDouyin, "d3", "b", time.Time{}, "旅行"),
+		testWork(videosdk.PlatformKuaishou, "k1", "a", day(2), "旅行"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"all by create time", Query{}, []string{"d2", "k1", "d1", "d3"}},
+		{"platform", Query{Platform: videosdk.PlatformDouyin}, []string{"d2", "d1", "d3"}},
+		{"author", Query{Platform: videosdk.PlatformDouyin, AuthorUID: "a"}, []string{"d2", "d1"}},
+		{"tag", Query{Tag: "旅行"}, []string{"k1", "d1", "d3"}},
+		{"tag and platform", Query{Tag: "旅行", Platform: videosdk.PlatformKuaishou}, []string{"k1"}},
+		{"from", Query{From: day(2)}, []string{"d2", "k1"}},
+		{"to excluded", Query{To: day(2)}, []string{"d1"}},
+		{"limit", Query{Limit: 2}, []string{"d2", "k1"}},
+		{"offset", Query{Offset: 3}, []string{"d3"}},
+		{"limit and offset", Query{Limit: 1, Offset: 1}, []string{"k1"}},
+		{"no match", Query{AuthorUID: "missing"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			works, err := store.FindWorks(ctx, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := workIDs(works); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("works = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	helpers := []struct {
+		name  string
+		works func() ([]*Work, error)
+		want  []string
+	}{
+		{"WorksByAuthor", func() ([]*Work, error) { return store.WorksByAuthor(ctx, videosdk.PlatformKuaishou, "a") }, []string{"k1"}},
+		{"WorksByTag", func() ([]*Work, error) { return store.WorksByTag(ctx, "旅行") }, []string{"k1", "d1", "d3"}},
+		{"WorksBetween", func() ([]*Work, error) { return store.WorksBetween(ctx, day(1), day(3)) }, []string{"k1", "d1"}},
+		{"WorksByPlatform", func() ([]*Work, error) { return store.WorksByPlatform(ctx, videosdk.PlatformKuaishou) }, []string{"k1"}},
+	}
+	for _, tt := range helpers {
+		t.Run(tt.name, func(t *testing.T) {
+			works, err := tt.works()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := workIDs(works); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("works = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// 删除作品同时删除标签和统计快照
+	if err := store.DeleteWork(ctx, videosdk.PlatformDouyin, "d1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteWork(ctx, videosdk.PlatformDouyin, "d1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("delete missing work: err = %v, want ErrNotFound", err)
+	}
+	if _, err := store.LatestSnapshot(ctx, videosdk.PlatformDouyin, "d1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("snapshot of deleted work: err = %v, want ErrNotFound", err)
+	}
+	if works, _ := store.WorksByTag(ctx, "旅行"); !reflect.DeepEqual(workIDs(works), []string{"k1", "d3"}) {
+		t.Errorf("works by tag after delete = %q", workIDs(works))
+	}
+}
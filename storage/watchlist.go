@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// WatchItem 统计追踪列表中的作品
+type WatchItem struct {
+	Platform  videosdk.Platform `json:"platform"`             // 平台
+	WorkID    string            `json:"work_id"`              // 作品ID
+	URL       string            `json:"url,omitempty"`        // 解析使用的作品链接（如带xsec_token的小红书链接），为空时按ID解析
+	Interval  time.Duration     `json:"interval,omitempty"`   // 采集间隔（纳秒），为0时使用追踪器的默认间隔
+	AddedAt   time.Time         `json:"added_at"`             // 加入时间
+	LastRun   time.Time         `json:"last_run"`             // 最近一次采集时间，未采集时为零值
+	NextRun   time.Time         `json:"next_run"`             // 下次采集时间
+	LastError string            `json:"last_error,omitempty"` // 最近一次采集失败的原因
+	Failures  int               `json:"failures,omitempty"`   // 连续失败次数
+}
+
+// SaveWatchItem 将作品加入追踪列表，已存在时更新链接、采集间隔和下次采集时间
+func (s *Store) SaveWatchItem(ctx context.Context, item *WatchItem) error {
+	if item.Platform == "" || item.WorkID == "" {
+		return fmt.Errorf("save watch item: platform and work id are required")
+	}
+	now := s.now()
+	if item.AddedAt.IsZero() {
+		item.AddedAt = now
+	}
+	if item.NextRun.IsZero() {
+		item.NextRun = now
+	}
+
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO watchlist (platform, work_id, url, interval, added_at, next_run)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (platform, work_id) DO UPDATE SET
+			url      = excluded.url,
+			interval = excluded.interval,
+			next_run = excluded.next_run`,
+		string(item.Platform), item.WorkID, item.URL, int64(item.Interval),
+		item.AddedAt.UnixNano(), item.NextRun.UnixNano(),
+	); err != nil {
+		return fmt.Errorf("save watch item %s/%s: %w", item.Platform, item.WorkID, err)
+	}
+	return nil
+}
+
+// RecordWatchRun 记录一次采集的结果和下次采集时间，runErr为空表示成功，作品不在追踪列表中时返回ErrNotFound
+func (s *Store) RecordWatchRun(ctx context.Context, platform videosdk.Platform, workID string, ranAt, nextRun time.Time, runErr string) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE watchlist SET
+			last_run   = ?,
+			next_run   = ?,
+			last_error = ?,
+			failures   = CASE WHEN ? = '' THEN 0 ELSE failures + 1 END
+		WHERE platform = ? AND work_id = ?`,
+		ranAt.UnixNano(), nextRun.UnixNano(), runErr, runErr, string(platform), workID,
+	)
+	if err != nil {
+		return fmt.Errorf("record watch run %s/%s: %w", platform, workID, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetWatchItem 获取追踪列表中的作品，不存在时返回ErrNotFound
+func (s *Store) GetWatchItem(ctx context.Context, platform videosdk.Platform, workID string) (*WatchItem, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+watchColumns+` FROM watchlist WHERE platform = ? AND work_id = ?`,
+		string(platform), workID,
+	)
+	item, err := scanWatchItem(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get watch item %s/%s: %w", platform, workID, err)
+	}
+	return item, nil
+}
+
+// WatchItems 列出追踪列表，按下次采集时间升序排列
+func (s *Store) WatchItems(ctx context.Context) ([]*WatchItem, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+watchColumns+` FROM watchlist ORDER BY next_run, platform, work_id`)
+	if err != nil {
+		return nil, fmt.Errorf("list watch items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*WatchItem
+	for rows.Next() {
+		item, err := scanWatchItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list watch items: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list watch items: %w", err)
+	}
+	return items, nil
+}
+
+// DeleteWatchItem 将作品移出追踪列表，已采集的统计快照保留，不存在时返回ErrNotFound
+func (s *Store) DeleteWatchItem(ctx context.Context, platform videosdk.Platform, workID string) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM watchlist WHERE platform = ? AND work_id = ?`,
+		string(platform), workID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete watch item %s/%s: %w", platform, workID, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// watchColumns scanWatchItem读取的列
+const watchColumns = `platform, work_id, url, interval, added_at, last_run, next_run, last_error, failures`
+
+// scanWatchItem 读取追踪列表行
+func scanWatchItem(row scanner) (*WatchItem, error) {
+	var (
+		item                      WatchItem
+		platform                  string
+		interval                  int64
+		addedAt, lastRun, nextRun sql.NullInt64
+	)
+	if err := row.Scan(
+		&platform, &item.WorkID, &item.URL, &interval, &addedAt, &lastRun, &nextRun, &item.LastError, &item.Failures,
+	); err != nil {
+		return nil, err
+	}
+	item.Platform = videosdk.Platform(platform)
+	item.Interval = time.Duration(interval)
+	item.AddedAt = scanTime(addedAt)
+	item.LastRun = scanTime(lastRun)
+	item.NextRun = scanTime(nextRun)
+	return &item, nil
+}
@@ -0,0 +1,248 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+)
+
+// queryer *sql.DB和*sql.Tx共有的方法
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// scanner *sql.Row和*sql.Rows共有的方法
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Query 作品查询条件，多个条件同时生效
+type Query struct {
+	Platform  videosdk.Platform // 平台，为空时不限制
+	AuthorUID string            // 作者用户ID，为空时不限制
+	Tag       string            // 标签，为空时不限制
+	From      time.Time         // 发布时间下限（含），零值时不限制
+	To        time.Time         // 发布时间上限（不含），零值时不限制
+	Limit     int               // 最多返回的数量，<=0时不限制
+	Offset    int               // 跳过的数量
+}
+
+// SaveWork 保存作品，按平台+作品ID新增或更新
+//
+// 同时更新作者信息和标签，并以当前时间记录一条统计快照。
+func (s *Store) SaveWork(ctx context.Context, info *videosdk.VideoInfo) error {
+	return s.SaveWorks(ctx, []*videosdk.VideoInfo{info})
+}
+
+// SaveWorks 在一个事务中保存多个作品，nil会被跳过
+func (s *Store) SaveWorks(ctx context.Context, infos []*videosdk.VideoInfo) error {
+	now := s.now()
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		for _, info := range infos {
+			if info == nil {
+				continue
+			}
+			if err := saveWork(ctx, tx, info, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// saveWork 保存作品、作者、标签和统计快照
+func saveWork(ctx context.Context, q queryer, info *videosdk.VideoInfo, now time.Time) error {
+	if info.Platform == "" || info.ID == "" {
+		return fmt.Errorf("save work: platform and id are required")
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("encode work %s/%s: %w", info.Platform, info.ID, err)
+	}
+
+	if info.Author.UID != "" {
+		if err := saveAuthor(ctx, q, info.Platform, &info.Author, now); err != nil {
+			return err
+		}
+	}
+
+	if _, err := q.ExecContext(ctx, `
+		INSERT INTO works (platform, id, author_uid, title, type, create_time, data, first_seen, last_seen)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (platform, id) DO UPDATE SET
+			author_uid  = excluded.author_uid,
+			title       = excluded.title,
+			type        = excluded.type,
+			create_time = excluded.create_time,
+			data        = excluded.data,
+			last_seen   = excluded.last_seen`,
+		string(info.Platform), info.ID, info.Author.UID, info.Title, string(info.Type),
+		timeValue(info.CreateTime), string(data), now.UnixNano(), now.UnixNano(),
+	); err != nil {
+		return fmt.Errorf("save work %s/%s: %w", info.Platform, info.ID, err)
+	}
+
+	if _, err := q.ExecContext(ctx,
+		`DELETE FROM work_tags WHERE platform = ? AND work_id = ?`,
+		string(info.Platform), info.ID,
+	); err != nil {
+		return fmt.Errorf("save work tags %s/%s: %w", info.Platform, info.ID, err)
+	}
+	for _, tag := range info.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, err := q.ExecContext(ctx,
+			`INSERT OR IGNORE INTO work_tags (platform, work_id, tag) VALUES (?, ?, ?)`,
+			string(info.Platform), info.ID, tag,
+		); err != nil {
+			return fmt.Errorf("save work tags %s/%s: %w", info.Platform, info.ID, err)
+		}
+	}
+
+	return addSnapshot(ctx, q, info.Platform, info.ID, info.Stats, now)
+}
+
+// GetWork 获取作品，不存在时返回ErrNotFound
+func (s *Store) GetWork(ctx context.Context, platform videosdk.Platform, id string) (*Work, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT data, first_seen, last_seen FROM works WHERE platform = ? AND id = ?`,
+		string(platform), id,
+	)
+	work, err := scanWork(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get work %s/%s: %w", platform, id, err)
+	}
+	return work, nil
+}
+
+// DeleteWork 删除作品及其标签、统计快照和文件记录（不删除磁盘上的文件），不存在时返回ErrNotFound
+func (s *Store) DeleteWork(ctx context.Context, platform videosdk.Platform, id string) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `DELETE FROM works WHERE platform = ? AND id = ?`, string(platform), id)
+		if err != nil {
+			return fmt.Errorf("delete work %s/%s: %w", platform, id, err)
+		}
+		if n, err := result.RowsAffected(); err == nil && n == 0 {
+			return ErrNotFound
+		}
+		for _, table := range []string{"work_tags", "stats_snapshots", "files"} {
+			if _, err := tx.ExecContext(ctx,
+				`DELETE FROM `+table+` WHERE platform = ? AND work_id = ?`,
+				string(platform), id,
+			); err != nil {
+				return fmt.Errorf("delete work %s/%s: %w", platform, id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// FindWorks 按条件查询作品，按发布时间倒序排列（发布时间未知的排在最后）
+func (s *Store) FindWorks(ctx context.Context, query Query) ([]*Work, error) {
+	sqlQuery := `SELECT w.data, w.first_seen, w.last_seen FROM works w`
+	var conditions []string
+	var args []interface{}
+	if query.Tag != "" {
+		sqlQuery += ` JOIN work_tags t ON t.platform = w.platform AND t.work_id = w.id`
+		conditions = append(conditions, `t.tag = ?`)
+		args = append(args, strings.TrimSpace(query.Tag))
+	}
+	if query.Platform != "" {
+		conditions = append(conditions, `w.platform = ?`)
+		args = append(args, string(query.Platform))
+	}
+	if query.AuthorUID != "" {
+		conditions = append(conditions, `w.author_uid = ?`)
+		args = append(args, query.AuthorUID)
+	}
+	if !query.From.IsZero() {
+		conditions = append(conditions, `w.create_time >= ?`)
+		args = append(args, query.From.UnixNano())
+	}
+	if !query.To.IsZero() {
+		conditions = append(conditions, `w.create_time < ?`)
+		args = append(args, query.To.UnixNano())
+	}
+	if len(conditions) > 0 {
+		sqlQuery += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	sqlQuery += ` ORDER BY w.create_time IS NULL, w.create_time DESC, w.platform, w.id`
+	if query.Limit > 0 || query.Offset > 0 {
+		limit := query.Limit
+		if limit <= 0 {
+			limit = -1
+		}
+		sqlQuery += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, query.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("find works: %w", err)
+	}
+	defer rows.Close()
+
+	var works []*Work
+	for rows.Next() {
+		work, err := scanWork(rows)
+		if err != nil {
+			return nil, fmt.Errorf("find works: %w", err)
+		}
+		works = append(works, work)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("find works: %w", err)
+	}
+	return works, nil
+}
+
+// WorksByAuthor 查询作者的全部作品
+func (s *Store) WorksByAuthor(ctx context.Context, platform videosdk.Platform, uid string) ([]*Work, error) {
+	return s.FindWorks(ctx, Query{Platform: platform, AuthorUID: uid})
+}
+
+// WorksByTag 查询带有标签的全部作品
+func (s *Store) WorksByTag(ctx context.Context, tag string) ([]*Work, error) {
+	return s.FindWorks(ctx, Query{Tag: tag})
+}
+
+// WorksBetween 查询发布时间在[from, to)内的作品
+func (s *Store) WorksBetween(ctx context.Context, from, to time.Time) ([]*Work, error) {
+	return s.FindWorks(ctx, Query{From: from, To: to})
+}
+
+// WorksByPlatform 查询平台的全部作品
+func (s *Store) WorksByPlatform(ctx context.Context, platform videosdk.Platform) ([]*Work, error) {
+	return s.FindWorks(ctx, Query{Platform: platform})
+}
+
+// scanWork 读取作品行（data, first_seen, last_seen）
+func scanWork(row scanner) (*Work, error) {
+	var (
+		data                sql.NullString
+		firstSeen, lastSeen sql.NullInt64
+	)
+	if err := row.Scan(&data, &firstSeen, &lastSeen); err != nil {
+		return nil, err
+	}
+
+	work := &Work{FirstSeen: scanTime(firstSeen), LastSeen: scanTime(lastSeen)}
+	if err := json.Unmarshal([]byte(data.String), &work.VideoInfo); err != nil {
+		return nil, fmt.Errorf("decode work: %w", err)
+	}
+	return work, nil
+}
@@ -0,0 +1,155 @@
+package videosdk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChinaLocation 平台时间统一使用的时区（Asia/Shanghai）
+var ChinaLocation = loadChinaLocation()
+
+// loadChinaLocation 加载Asia/Shanghai时区，系统缺少时区数据时回退为固定的UTC+8
+func loadChinaLocation() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Shanghai"); err == nil {
+		return loc
+	}
+	return time.FixedZone("CST", 8*60*60)
+}
+
+// timeLayouts 后端返回的常见时间格式
+var timeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02_15:04:05",
+	"2006-01-02T15:04:05",
+	"2006/01/02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"20060102",
+}
+
+// ParseTime 解析平台返回的时间
+//
+// 支持常见的日期时间格式（按Asia/Shanghai时区解释，包括"20231015"这样的紧凑日期）、
+// 带时区的RFC3339格式，以及10位秒级或13位毫秒级的Unix时间戳。无法解析时返回零值，而不是伪造当前时间。
+func ParseTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return time.Time{}
+	}
+
+	if ts, ok := unixTimestamp(value); ok {
+		return ParseUnixTime(ts)
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(ChinaLocation)
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, ChinaLocation); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// unixTimestamp 识别10位秒级或13位毫秒级的Unix时间戳（秒级可以带小数），其余数字不视为时间戳
+func unixTimestamp(value string) (float64, bool) {
+	integer, fraction, hasFraction := strings.Cut(value, ".")
+	if len(integer) != 10 && len(integer) != 13 {
+		return 0, false
+	}
+	if !isDigits(integer) || (hasFraction && (len(integer) != 10 || !isDigits(fraction))) {
+		return 0, false
+	}
+	ts, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return ts, true
+}
+
+// isDigits 是否为非空的纯数字
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseUnixTime 将秒级或毫秒级的Unix时间戳转换为Asia/Shanghai时区的时间
+func ParseUnixTime(ts float64) time.Time {
+	if ts <= 0 {
+		return time.Time{}
+	}
+
+	// 大于1e12的数值视为毫秒时间戳（1e12秒已超出合理范围）
+	if ts >= 1e12 {
+		return time.UnixMilli(int64(ts)).In(ChinaLocation)
+	}
+
+	sec := int64(ts)
+	nsec := int64((ts - float64(sec)) * float64(time.Second))
+	return time.Unix(sec, nsec).In(ChinaLocation)
+}
+
+// ParseDuration 解析平台返回的时长
+//
+// 支持"HH:MM:SS"、"MM:SS"格式、Go时长格式（如"1m30s"）以及毫秒数。
+// 无法解析时返回0。
+func ParseDuration(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	// 纯数字按毫秒处理（各平台原始数据的时长单位均为毫秒）
+	if ms, err := strconv.ParseFloat(value, 64); err == nil {
+		if ms <= 0 {
+			return 0
+		}
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+
+	if strings.Contains(value, ":") {
+		parts := strings.Split(value, ":")
+		if len(parts) > 3 {
+			return 0
+		}
+
+		var total float64
+		for _, part := range parts {
+			n, err := strconv.ParseFloat(part, 64)
+			if err != nil || n < 0 {
+				return 0
+			}
+			total = total*60 + n
+		}
+		return time.Duration(total * float64(time.Second))
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+
+	return 0
+}
+
+// FormatDuration 将时长格式化为"HH:MM:SS"
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+
+	total := int64(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, total%3600/60, total%60)
+}
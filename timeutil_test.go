@@ -0,0 +1,33 @@
+package videosdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2023-10-15 08:30:00", time.Date(2023, 10, 15, 8, 30, 0, 0, ChinaLocation)},
+		{"2024-09-13_19:12:34", time.Date(2024, 9, 13, 19, 12, 34, 0, ChinaLocation)},
+		{"20231015", time.Date(2023, 10, 15, 0, 0, 0, 0, ChinaLocation)},
+		{"2023-10-15T00:30:00Z", time.Date(2023, 10, 15, 8, 30, 0, 0, ChinaLocation)},
+		{"1697329800", time.Date(2023, 10, 15, 8, 30, 0, 0, ChinaLocation)},
+		{"1697329800000", time.Date(2023, 10, 15, 8, 30, 0, 0, ChinaLocation)},
+		{"1697329800.5", time.Date(2023, 10, 15, 8, 30, 0, int(500*time.Millisecond), ChinaLocation)},
+		{"", time.Time{}},
+		{"0", time.Time{}},
+		{"12345", time.Time{}},
+		{"169732980", time.Time{}},
+		{"1697329800000.5", time.Time{}},
+		{"昨天", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseTime(tt.value); !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
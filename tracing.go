@@ -0,0 +1,177 @@
+package videosdk
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Attribute 追踪属性
+type Attribute struct {
+	Key   string      // 属性名
+	Value interface{} // 属性值（string、int、int64、float64、bool）
+}
+
+// Span 追踪中的一个操作，接口与OpenTelemetry的Span保持一致的语义，便于适配
+type Span interface {
+	// SetAttributes 设置属性
+	SetAttributes(attrs ...Attribute)
+
+	// RecordError 记录错误并将操作标记为失败
+	RecordError(err error)
+
+	// End 结束操作
+	End()
+}
+
+// Tracer 创建Span的追踪器
+//
+// 核心包不依赖任何追踪系统，接入OpenTelemetry使用otel子包提供的适配器，其他系统可以自行实现本接口。
+type Tracer interface {
+	// Start 开始一个操作，返回携带该Span的上下文
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// tracerKey 上下文中追踪器的键
+type tracerKey struct{}
+
+// withTracer 将追踪器放入上下文，供解析器创建子Span
+func withTracer(ctx context.Context, tracer Tracer) context.Context {
+	if tracer == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// StartSpan 使用上下文中的追踪器开始一个操作，未设置追踪器时返回空操作Span
+func StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	tracer, ok := ctx.Value(tracerKey{}).(Tracer)
+	if !ok {
+		return ctx, noopSpan{}
+	}
+	return tracer.Start(ctx, name, attrs...)
+}
+
+// noopSpan 未设置追踪器时使用的空操作Span
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// SpanData 内存追踪器记录的Span
+type SpanData struct {
+	ID         int                    `json:"id"`              // Span编号（从1开始）
+	ParentID   int                    `json:"parent_id"`       // 父Span编号，0表示根Span
+	Name       string                 `json:"name"`            // 操作名
+	Attributes map[string]interface{} `json:"attributes"`      // 属性
+	Error      string                 `json:"error,omitempty"` // 错误信息
+	Start      time.Time              `json:"start"`           // 开始时间
+	End        time.Time              `json:"end"`             // 结束时间
+}
+
+// Duration 操作耗时
+func (d SpanData) Duration() time.Duration {
+	return d.End.Sub(d.Start)
+}
+
+// InMemoryTracer 在内存中记录已结束Span的追踪器，用于测试和调试
+type InMemoryTracer struct {
+	mu     sync.Mutex
+	nextID int
+	spans  []SpanData
+}
+
+// NewInMemoryTracer 创建内存追踪器
+func NewInMemoryTracer() *InMemoryTracer {
+	return &InMemoryTracer{}
+}
+
+// inMemorySpanKey 上下文中当前内存Span的键
+type inMemorySpanKey struct{}
+
+// Start 开始一个操作
+func (t *InMemoryTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	t.mu.Lock()
+	t.nextID++
+	id := t.nextID
+	t.mu.Unlock()
+
+	span := &inMemorySpan{
+		tracer: t,
+		data: SpanData{
+			ID:         id,
+			Name:       name,
+			Attributes: make(map[string]interface{}),
+			Start:      time.Now(),
+		},
+	}
+	if parent, ok := ctx.Value(inMemorySpanKey{}).(*inMemorySpan); ok && parent.tracer == t {
+		span.data.ParentID = parent.data.ID
+	}
+	span.SetAttributes(attrs...)
+
+	return context.WithValue(ctx, inMemorySpanKey{}, span), span
+}
+
+// Spans 获取全部已结束的Span，按结束顺序排列
+func (t *InMemoryTracer) Spans() []SpanData {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]SpanData(nil), t.spans...)
+}
+
+// Reset 清空已记录的Span
+func (t *InMemoryTracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = nil
+}
+
+// inMemorySpan 内存追踪器的Span
+type inMemorySpan struct {
+	tracer *InMemoryTracer
+	mu     sync.Mutex
+	data   SpanData
+	ended  bool
+}
+
+// SetAttributes 设置属性
+func (s *inMemorySpan) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, attr := range attrs {
+		s.data.Attributes[attr.Key] = attr.Value
+	}
+}
+
+// RecordError 记录错误
+func (s *inMemorySpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Error = err.Error()
+}
+
+// End 结束操作并交给追踪器记录，重复调用无效
+func (s *inMemorySpan) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	data.Attributes = make(map[string]interface{}, len(s.data.Attributes))
+	for k, v := range s.data.Attributes {
+		data.Attributes[k] = v
+	}
+	s.mu.Unlock()
+
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.spans = append(s.tracer.spans, data)
+}
@@ -0,0 +1,82 @@
+package tracker
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Format 导出格式
+type Format string
+
+const (
+	FormatCSV  Format = "csv"  // 每个快照一行，包含各指标的值、增量和每小时增量
+	FormatJSON Format = "json" // Series数组
+)
+
+// Export 按格式导出时间序列
+func Export(w io.Writer, format Format, series ...*Series) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, series...)
+	case FormatJSON, "":
+		return WriteJSON(w, series...)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
+
+// WriteJSON 以JSON数组导出时间序列
+func WriteJSON(w io.Writer, series ...*Series) error {
+	if series == nil {
+		series = []*Series{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(series)
+}
+
+// WriteCSV 以CSV导出时间序列
+//
+// 列为platform、work_id、captured_at（RFC3339），以及每个指标的值、<指标>_delta和<指标>_per_hour，
+// 未知的值和每个作品第一行的增量为空。
+func WriteCSV(w io.Writer, series ...*Series) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"platform", "work_id", "captured_at"}
+	for _, metric := range Metrics {
+		header = append(header, string(metric), string(metric)+"_delta", string(metric)+"_per_hour")
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, s := range series {
+		for _, point := range s.Points {
+			record := []string{string(s.Platform), s.WorkID, point.CapturedAt.Format(time.RFC3339)}
+			for _, metric := range Metrics {
+				value, delta, perHour := "", "", ""
+				if count := metric.Value(point.Stats); count >= 0 {
+					value = strconv.FormatInt(int64(count), 10)
+				}
+				if point.Change != nil {
+					if d, ok := point.Change.Delta[metric]; ok {
+						delta = strconv.FormatInt(d, 10)
+					}
+					if rate, ok := point.Change.PerHour[metric]; ok {
+						perHour = strconv.FormatFloat(rate, 'f', 2, 64)
+					}
+				}
+				record = append(record, value, delta, perHour)
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
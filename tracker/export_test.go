@@ -0,0 +1,92 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/storage"
+)
+
+func TestWriteCSV(t *testing.T) {
+	series := []*Series{
+		NewSeries(videosdk.PlatformDouyin, "1", []storage.Snapshot{
+			snapshot(0, 100, 10, unknown, 0, 0),
+			snapshot(2, 300, 11, 5, 0, 0),
+		}),
+		NewSeries(videosdk.PlatformKuaishou, "k1", nil),
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, series...); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"platform,work_id,captured_at," +
+			"play_count,play_count_delta,play_count_per_hour," +
+			"like_count,like_count_delta,like_count_per_hour," +
+			"comment_count,comment_count_delta,comment_count_per_hour," +
+			"share_count,share_count_delta,share_count_per_hour," +
+			"collect_count,collect_count_delta,collect_count_per_hour",
+		"douyin,1,2024-09-01T08:00:00Z,100,,,10,,,,,,0,,,0,,",
+		"douyin,1,2024-09-01T10:00:00Z,300,200,100.00,11,1,0.50,5,,,0,0,0.00,0,0,0.00",
+	}, "\n") + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("csv =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	series := NewSeries(videosdk.PlatformDouyin, "1", []storage.Snapshot{
+		snapshot(0, 100, unknown, 0, 0, 0),
+		snapshot(1, 150, unknown, 0, 0, 0),
+	})
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, series); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []*Series
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || len(decoded[0].Points) != 2 || decoded[0].Total == nil {
+		t.Fatalf("decoded = %s", buf.String())
+	}
+	if decoded[0].Points[1].Stats.LikeCount != unknown || !reflect.DeepEqual(decoded[0].Total.Delta, series.Total.Delta) {
+		t.Errorf("decoded = %s", buf.String())
+	}
+	// 未知的数量导出为null
+	if !strings.Contains(buf.String(), `"like_count": null`) {
+		t.Errorf("unknown count not exported as null: %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteJSON(&buf); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("empty export = %q, %v, want []", buf.String(), err)
+	}
+}
+
+func TestExportFormat(t *testing.T) {
+	series := NewSeries(videosdk.PlatformDouyin, "1", nil)
+	tests := []struct {
+		format Format
+		prefix string
+	}{
+		{FormatCSV, "platform,work_id"},
+		{FormatJSON, "["},
+		{"", "["},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Export(&buf, tt.format, series); err != nil || !strings.HasPrefix(buf.String(), tt.prefix) {
+			t.Errorf("export %q = %q, %v", tt.format, buf.String(), err)
+		}
+	}
+	if err := Export(&bytes.Buffer{}, "xml", series); err == nil {
+		t.Error("export xml: want error")
+	}
+}
@@ -0,0 +1,108 @@
+package tracker
+
+import (
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/storage"
+)
+
+// Metric 统计指标
+type Metric string
+
+const (
+	MetricPlay    Metric = "play_count"    // 播放量
+	MetricLike    Metric = "like_count"    // 点赞数
+	MetricComment Metric = "comment_count" // 评论数
+	MetricShare   Metric = "share_count"   // 分享数
+	MetricCollect Metric = "collect_count" // 收藏数
+)
+
+// Metrics 全部统计指标，导出时按此顺序输出
+var Metrics = []Metric{MetricPlay, MetricLike, MetricComment, MetricShare, MetricCollect}
+
+// Value 从统计数据中取出指标的值
+func (m Metric) Value(stats videosdk.VideoStats) videosdk.Count {
+	switch m {
+	case MetricPlay:
+		return stats.PlayCount
+	case MetricLike:
+		return stats.LikeCount
+	case MetricComment:
+		return stats.CommentCount
+	case MetricShare:
+		return stats.ShareCount
+	case MetricCollect:
+		return stats.CollectCount
+	}
+	return videosdk.CountUnknown
+}
+
+// Change 两次快照之间的统计变化
+//
+// 只包含两次快照中都已知的指标，未知或被隐藏的指标不出现在结果中。
+type Change struct {
+	From    time.Time          `json:"from"`             // 起始快照的采集时间
+	To      time.Time          `json:"to"`               // 结束快照的采集时间
+	Delta   map[Metric]int64   `json:"delta"`            // 增量，可能为负（如取消点赞）
+	PerHour map[Metric]float64 `json:"per_hour"`         // 平均每小时的增量
+	Growth  map[Metric]float64 `json:"growth,omitempty"` // 相对起始值的增长率（0.1表示增长10%），起始值为0时不计算
+}
+
+// Compare 计算两次快照之间的统计变化
+func Compare(from, to storage.Snapshot) *Change {
+	change := &Change{
+		From:    from.CapturedAt,
+		To:      to.CapturedAt,
+		Delta:   make(map[Metric]int64),
+		PerHour: make(map[Metric]float64),
+		Growth:  make(map[Metric]float64),
+	}
+	hours := to.CapturedAt.Sub(from.CapturedAt).Hours()
+	for _, metric := range Metrics {
+		start, end := metric.Value(from.Stats), metric.Value(to.Stats)
+		if start < 0 || end < 0 {
+			continue
+		}
+		delta := int64(end) - int64(start)
+		change.Delta[metric] = delta
+		if hours > 0 {
+			change.PerHour[metric] = float64(delta) / hours
+		}
+		if start > 0 {
+			change.Growth[metric] = float64(delta) / float64(start)
+		}
+	}
+	return change
+}
+
+// Point 时间序列中的一次快照
+type Point struct {
+	CapturedAt time.Time           `json:"captured_at"`      // 采集时间
+	Stats      videosdk.VideoStats `json:"stats"`            // 统计数据
+	Change     *Change             `json:"change,omitempty"` // 相对上一次快照的变化，第一个点为nil
+}
+
+// Series 作品的统计时间序列
+type Series struct {
+	Platform videosdk.Platform `json:"platform"`        // 平台
+	WorkID   string            `json:"work_id"`         // 作品ID
+	Points   []Point           `json:"points"`          // 按采集时间升序排列的快照
+	Total    *Change           `json:"total,omitempty"` // 第一次到最后一次快照的变化，快照少于两个时为nil
+}
+
+// NewSeries 由按采集时间升序排列的快照构建时间序列
+func NewSeries(platform videosdk.Platform, workID string, snapshots []storage.Snapshot) *Series {
+	series := &Series{Platform: platform, WorkID: workID, Points: make([]Point, 0, len(snapshots))}
+	for i, snapshot := range snapshots {
+		point := Point{CapturedAt: snapshot.CapturedAt, Stats: snapshot.Stats}
+		if i > 0 {
+			point.Change = Compare(snapshots[i-1], snapshot)
+		}
+		series.Points = append(series.Points, point)
+	}
+	if len(snapshots) > 1 {
+		series.Total = Compare(snapshots[0], snapshots[len(snapshots)-1])
+	}
+	return series
+}
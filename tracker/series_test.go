@@ -0,0 +1,145 @@
+package tracker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/storage"
+)
+
+var testStart = time.Date(2024, 9, 1, 8, 0, 0, 0, time.UTC)
+
+// snapshot 构造at小时后采集的快照，统计数量依次为播放、点赞、评论、分享、收藏
+func snapshot(hours float64, play, like, comment, share, collect videosdk.Count) storage.Snapshot {
+	return storage.Snapshot{
+		Platform:   videosdk.PlatformDouyin,
+		WorkID:     "1",
+		CapturedAt: testStart.Add(time.Duration(hours * float64(time.Hour))),
+		Stats: videosdk.VideoStats{
+			PlayCount:    play,
+			LikeCount:    like,
+			CommentCount: comment,
+			ShareCount:   share,
+			CollectCount: collect,
+		},
+	}
+}
+
+const unknown = videosdk.CountUnknown
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to storage.Snapshot
+		delta    map[Metric]int64
+		perHour  map[Metric]float64
+		growth   map[Metric]float64
+	}{
+		{
+			name:    "growth",
+			from:    snapshot(0, 100, 10, 4, 2, 1),
+			to:      snapshot(2, 300, 15, 4, 2, 3),
+			delta:   map[Metric]int64{MetricPlay: 200, MetricLike: 5, MetricComment: 0, MetricShare: 0, MetricCollect: 2},
+			perHour: map[Metric]float64{MetricPlay: 100, MetricLike: 2.5, MetricComment: 0, MetricShare: 0, MetricCollect: 1},
+			growth:  map[Metric]float64{MetricPlay: 2, MetricLike: 0.5, MetricComment: 0, MetricShare: 0, MetricCollect: 2},
+		},
+		{
+			name:    "decrease",
+			from:    snapshot(0, 100, 10, 0, 0, 0),
+			to:      snapshot(0.5, 100, 8, 0, 0, 0),
+			delta:   map[Metric]int64{MetricPlay: 0, MetricLike: -2, MetricComment: 0, MetricShare: 0, MetricCollect: 0},
+			perHour: map[Metric]float64{MetricPlay: 0, MetricLike: -4, MetricComment: 0, MetricShare: 0, MetricCollect: 0},
+			growth:  map[Metric]float64{MetricPlay: 0, MetricLike: -0.2},
+		},
+		{
+			name:    "unknown metrics skipped",
+			from:    snapshot(0, unknown, 10, 5, unknown, 0),
+			to:      snapshot(1, 500, unknown, 6, unknown, 4),
+			delta:   map[Metric]int64{MetricComment: 1, MetricCollect: 4},
+			perHour: map[Metric]float64{MetricComment: 1, MetricCollect: 4},
+			growth:  map[Metric]float64{MetricComment: 0.2},
+		},
+		{
+			name:    "same capture time",
+			from:    snapshot(1, 100, 0, 0, 0, 0),
+			to:      snapshot(1, 150, 0, 0, 0, 0),
+			delta:   map[Metric]int64{MetricPlay: 50, MetricLike: 0, MetricComment: 0, MetricShare: 0, MetricCollect: 0},
+			perHour: map[Metric]float64{},
+			growth:  map[Metric]float64{MetricPlay: 0.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := Compare(tt.from, tt.to)
+			if !change.From.Equal(tt.from.CapturedAt) || !change.To.Equal(tt.to.CapturedAt) {
+				t.Errorf("range = %v - %v", change.From, change.To)
+			}
+			if !reflect.DeepEqual(change.Delta, tt.delta) {
+				t.Errorf("delta = %v, want %v", change.Delta, tt.delta)
+			}
+			if !reflect.DeepEqual(change.PerHour, tt.perHour) {
+				t.Errorf("per hour = %v, want %v", change.PerHour, tt.perHour)
+			}
+			if !reflect.DeepEqual(change.Growth, tt.growth) {
+				t.Errorf("growth = %v, want %v", change.Growth, tt.growth)
+			}
+		})
+	}
+}
+
+func TestNewSeries(t *testing.T) {
+	snapshots := []storage.Snapshot{
+		snapshot(0, 100, 10, 0, 0, 0),
+		snapshot(1, 160, unknown, 0, 0, 0),
+		snapshot(3, 400, 20, 0, 0, 0),
+	}
+
+	tests := []struct {
+		name      string
+		snapshots []storage.Snapshot
+		total     map[Metric]int64
+	}{
+		{"empty", nil, nil},
+		{"single", snapshots[:1], nil},
+		{"multiple", snapshots, map[Metric]int64{MetricPlay: 300, MetricLike: 10, MetricComment: 0, MetricShare: 0, MetricCollect: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := NewSeries(videosdk.PlatformDouyin, "1", tt.snapshots)
+			if series.Platform != videosdk.PlatformDouyin || series.WorkID != "1" || len(series.Points) != len(tt.snapshots) {
+				t.Fatalf("series = %+v", series)
+			}
+			if series.Points == nil {
+				t.Error("points is nil, want empty slice")
+			}
+			for i, point := range series.Points {
+				if !point.CapturedAt.Equal(tt.snapshots[i].CapturedAt) || point.Stats != tt.snapshots[i].Stats {
+					t.Errorf("point %d = %+v", i, point)
+				}
+				if (i == 0) != (point.Change == nil) {
+					t.Errorf("point %d change = %+v", i, point.Change)
+				}
+			}
+			if tt.total == nil {
+				if series.Total != nil {
+					t.Errorf("total = %+v, want nil", series.Total)
+				}
+				return
+			}
+			if series.Total == nil || !reflect.DeepEqual(series.Total.Delta, tt.total) {
+				t.Errorf("total = %+v, want delta %v", series.Total, tt.total)
+			}
+		})
+	}
+
+	// 相邻快照中有未知值时跳过该指标，总变化仍按首尾快照计算
+	series := NewSeries(videosdk.PlatformDouyin, "1", snapshots)
+	if _, ok := series.Points[1].Change.Delta[MetricLike]; ok {
+		t.Errorf("point 1 like delta = %v, want skipped", series.Points[1].Change.Delta)
+	}
+	if rate := series.Points[2].Change.PerHour[MetricPlay]; rate != 120 {
+		t.Errorf("point 2 play per hour = %v, want 120", rate)
+	}
+}
@@ -0,0 +1,398 @@
+// Package tracker 按计划重复解析追踪列表中的作品，记录统计数据的时间序列
+//
+// Tracker定期重新解析追踪列表中的作品，将带时间戳的统计快照保存到storage.Store，
+// 并据此计算增量和增长率。采集时间带有随机抖动，并按平台限流，避免固定频率的请求触发平台风控：
+//
+//	store, _ := storage.Open("archive.db")
+//	t := tracker.New(sdk, store, tracker.Config{Interval: time.Hour})
+//	_ = t.Start(ctx)
+//	defer t.Close()
+//
+//	_, _ = t.Watch(ctx, &storage.WatchItem{URL: "https://www.kuaishou.com/short-video/3xk8fz5m2q9wdqa"})
+//	series, _ := t.Series(ctx, videosdk.PlatformKuaishou, "3xk8fz5m2q9wdqa", time.Time{}, time.Time{})
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/storage"
+)
+
+// maxScheduleWait 调度协程两次检查追踪列表的最长间隔，用于发现其他进程加入的作品
+const maxScheduleWait = time.Minute
+
+// Config 追踪器配置
+type Config struct {
+	Interval    time.Duration      // 默认采集间隔，默认1小时
+	MinInterval time.Duration      // 作品允许设置的最短采集间隔，默认1分钟
+	Jitter      float64            // 采集间隔的随机抖动比例，0.1表示在间隔的±10%内随机，默认0.1，<0时不抖动
+	RateLimit   videosdk.LimitRule // 每个平台的限流规则，默认每5秒1次、同时1个请求
+	Workers     int                // 同时采集的作品数，默认2
+	Timeout     time.Duration      // 单次解析的超时时间，默认30秒
+	Logger      *slog.Logger       // 日志，默认使用slog.Default()
+}
+
+// Tracker 作品统计追踪器
+type Tracker struct {
+	sdk     videosdk.SDK
+	store   *storage.Store
+	config  Config
+	logger  *slog.Logger
+	limiter *videosdk.RateLimiter
+
+	mu      sync.Mutex
+	polling map[string]bool // 正在采集的作品
+	wake    chan struct{}
+	work    chan *storage.WatchItem
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New 创建追踪器，调用Start后开始按计划采集
+func New(sdk videosdk.SDK, store *storage.Store, config Config) *Tracker {
+	if config.Interval <= 0 {
+		config.Interval = time.Hour
+	}
+	if config.MinInterval <= 0 {
+		config.MinInterval = time.Minute
+	}
+	if config.Jitter < 0 {
+		config.Jitter = 0
+	} else if config.Jitter == 0 {
+		config.Jitter = 0.1
+	}
+	if config.Jitter > 1 {
+		config.Jitter = 1
+	}
+	if config.RateLimit == (videosdk.LimitRule{}) {
+		config.RateLimit = videosdk.LimitRule{Rate: 0.2, Burst: 1, MaxInFlight: 1}
+	}
+	if config.Workers <= 0 {
+		config.Workers = 2
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	limiter := videosdk.NewRateLimiter()
+	limiter.SetDefaultLimit(videosdk.LimitScopePlatform, config.RateLimit)
+
+	return &Tracker{
+		sdk:     sdk,
+		store:   store,
+		config:  config,
+		logger:  logger,
+		limiter: limiter,
+		polling: make(map[string]bool),
+		wake:    make(chan struct{}, 1),
+		work:    make(chan *storage.WatchItem),
+	}
+}
+
+// Start 启动调度协程和采集协程
+func (t *Tracker) Start(ctx context.Context) error {
+	if t.ctx != nil {
+		return fmt.Errorf("tracker is already started")
+	}
+	t.ctx, t.cancel = context.WithCancel(context.WithoutCancel(ctx))
+
+	t.wg.Add(1)
+	go t.schedule()
+	for i := 0; i < t.config.Workers; i++ {
+		t.wg.Add(1)
+		go t.worker()
+	}
+	return nil
+}
+
+// Close 停止采集并等待协程退出，不关闭存储
+func (t *Tracker) Close() error {
+	if t.cancel == nil {
+		return nil
+	}
+	t.cancel()
+	t.wg.Wait()
+	return nil
+}
+
+// Watch 将作品加入追踪列表并尽快采集一次
+//
+// 只设置URL时从链接中识别平台和作品ID（短链接需要先解析）。已在列表中的作品会更新链接和采集间隔。
+func (t *Tracker) Watch(ctx context.Context, item *storage.WatchItem) (*storage.WatchItem, error) {
+	if item == nil {
+		return nil, videosdk.NewError(videosdk.ErrCodeInvalidRequest, fmt.Errorf("watch item cannot be nil"))
+	}
+	item = &storage.WatchItem{
+		Platform: item.Platform,
+		WorkID:   item.WorkID,
+		URL:      item.URL,
+		Interval: item.Interval,
+	}
+	if item.WorkID == "" && item.URL != "" {
+		canonical, err := videosdk.Canonicalize(item.URL)
+		if err != nil {
+			return nil, err
+		}
+		if canonical.Short {
+			return nil, videosdk.NewError(videosdk.ErrCodeInvalidRequest,
+				fmt.Errorf("short link %s must be resolved before watching", canonical.URL))
+		}
+		if item.Platform == "" {
+			item.Platform = canonical.Platform
+		}
+		item.WorkID = canonical.ID
+	}
+	if item.Platform == "" || item.WorkID == "" {
+		return nil, videosdk.NewError(videosdk.ErrCodeInvalidRequest, fmt.Errorf("platform and work id or url are required"))
+	}
+	if item.Interval < 0 || (item.Interval > 0 && item.Interval < t.config.MinInterval) {
+		return nil, videosdk.NewError(videosdk.ErrCodeInvalidRequest,
+			fmt.Errorf("interval must be at least %s", t.config.MinInterval))
+	}
+
+	if err := t.store.SaveWatchItem(ctx, item); err != nil {
+		return nil, err
+	}
+	t.notify()
+	return t.store.GetWatchItem(ctx, item.Platform, item.WorkID)
+}
+
+// Unwatch 将作品移出追踪列表，已采集的快照保留，不在列表中时返回storage.ErrNotFound
+func (t *Tracker) Unwatch(ctx context.Context, platform videosdk.Platform, workID string) error {
+	return t.store.DeleteWatchItem(ctx, platform, workID)
+}
+
+// Watchlist 列出追踪列表，按下次采集时间升序排列
+func (t *Tracker) Watchlist(ctx context.Context) ([]*storage.WatchItem, error) {
+	return t.store.WatchItems(ctx)
+}
+
+// Series 获取作品在[from, to)内的统计时间序列，from或to为零值时不限制
+func (t *Tracker) Series(ctx context.Context, platform videosdk.Platform, workID string, from, to time.Time) (*Series, error) {
+	snapshots, err := t.store.Snapshots(ctx, platform, workID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return NewSeries(platform, workID, snapshots), nil
+}
+
+// Export 导出追踪列表中全部作品在[from, to)内的时间序列
+func (t *Tracker) Export(ctx context.Context, w io.Writer, format Format, from, to time.Time) error {
+	items, err := t.store.WatchItems(ctx)
+	if err != nil {
+		return err
+	}
+	series := make([]*Series, 0, len(items))
+	for _, item := range items {
+		s, err := t.Series(ctx, item.Platform, item.WorkID, from, to)
+		if err != nil {
+			return err
+		}
+		series = append(series, s)
+	}
+	return Export(w, format, series...)
+}
+
+// RunOnce 立即采集所有已到期的作品并等待完成，返回采集成功的数量
+//
+// 适合不调用Start、由外部定时任务驱动的场景，仍然遵守限流规则。
+func (t *Tracker) RunOnce(ctx context.Context) (int, error) {
+	items, err := t.store.WatchItems(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		polled int
+		errs   []error
+	)
+	now := time.Now()
+	for _, item := range items {
+		if item.NextRun.After(now) {
+			break
+		}
+		if !t.acquire(item) {
+			continue
+		}
+		err := t.poll(ctx, item)
+		t.release(item)
+		if ctx.Err() != nil {
+			return polled, ctx.Err()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", item.Platform, item.WorkID, err))
+			continue
+		}
+		polled++
+	}
+	return polled, errors.Join(errs...)
+}
+
+// schedule 调度协程，将到期的作品交给采集协程
+func (t *Tracker) schedule() {
+	defer t.wg.Done()
+	for {
+		timer := time.NewTimer(t.dispatchDue())
+		select {
+		case <-t.ctx.Done():
+			timer.Stop()
+			return
+		case <-t.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// dispatchDue 分发已到期的作品，返回距离下一个作品到期的时间
+func (t *Tracker) dispatchDue() time.Duration {
+	items, err := t.store.WatchItems(t.ctx)
+	if err != nil {
+		if t.ctx.Err() == nil {
+			t.logger.Error("load watchlist failed", "error", err)
+		}
+		return maxScheduleWait
+	}
+
+	for _, item := range items {
+		if wait := time.Until(item.NextRun); wait > 0 {
+			if wait > maxScheduleWait {
+				wait = maxScheduleWait
+			}
+			return wait
+		}
+		if !t.acquire(item) {
+			continue
+		}
+		select {
+		case t.work <- item:
+		case <-t.ctx.Done():
+			t.release(item)
+			return 0
+		}
+	}
+	return maxScheduleWait
+}
+
+// worker 采集协程
+func (t *Tracker) worker() {
+	defer t.wg.Done()
+	for {
+		select {
+		case item := <-t.work:
+			_ = t.poll(t.ctx, item)
+			t.release(item)
+		case <-t.ctx.Done():
+			return
+		}
+	}
+}
+
+// poll 采集一次作品统计并安排下次采集，ctx被取消时不记录结果
+func (t *Tracker) poll(ctx context.Context, item *storage.WatchItem) error {
+	done, err := t.limiter.Acquire(ctx, videosdk.LimitKey{Scope: videosdk.LimitScopePlatform, Key: string(item.Platform)})
+	if err != nil {
+		return err
+	}
+	// 结果由下面的SaveWork保存，避免归档中间件重复记录快照
+	parseCtx, cancel := context.WithTimeout(storage.SkipArchive(ctx), t.config.Timeout)
+	resp, err := t.sdk.ParseVideo(parseCtx, &videosdk.ParseRequest{
+		Platform: item.Platform,
+		VideoID:  item.WorkID,
+		URL:      item.URL,
+	})
+	cancel()
+	done()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err == nil && (resp == nil || !resp.Success || resp.Data == nil) {
+		err = fmt.Errorf("parse failed")
+		if resp != nil && resp.Error != "" {
+			err = videosdk.NewError(resp.Code, errors.New(resp.Error))
+		}
+	}
+	if err == nil {
+		err = t.store.SaveWork(ctx, resp.Data)
+	}
+
+	now := time.Now()
+	failures := 0
+	runErr := ""
+	if err != nil {
+		failures = item.Failures + 1
+		runErr = err.Error()
+		t.logger.Warn("track work failed",
+			"platform", item.Platform,
+			"id", item.WorkID,
+			"failures", failures,
+			"error", err,
+		)
+	} else {
+		t.logger.Debug("track work", "platform", item.Platform, "id", item.WorkID)
+	}
+
+	next := now.Add(t.nextInterval(item.Interval, failures))
+	recordErr := t.store.RecordWatchRun(context.WithoutCancel(ctx), item.Platform, item.WorkID, now, next, runErr)
+	if recordErr != nil && !errors.Is(recordErr, storage.ErrNotFound) {
+		t.logger.Error("save watch item failed", "platform", item.Platform, "id", item.WorkID, "error", recordErr)
+	}
+	return err
+}
+
+// nextInterval 计算到下次采集的间隔：连续失败时间隔翻倍（最多8倍），再加上随机抖动
+func (t *Tracker) nextInterval(interval time.Duration, failures int) time.Duration {
+	if interval <= 0 {
+		interval = t.config.Interval
+	}
+	if failures > 3 {
+		failures = 3
+	}
+	interval <<= failures
+	if t.config.Jitter > 0 {
+		interval += time.Duration(float64(interval) * t.config.Jitter * (2*rand.Float64() - 1))
+	}
+	return interval
+}
+
+// acquire 标记作品正在采集，已在采集中时返回false
+func (t *Tracker) acquire(item *storage.WatchItem) bool {
+	key := string(item.Platform) + ":" + item.WorkID
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.polling[key] {
+		return false
+	}
+	t.polling[key] = true
+	return true
+}
+
+// release 清除作品的采集标记
+func (t *Tracker) release(item *storage.WatchItem) {
+	t.mu.Lock()
+	delete(t.polling, string(item.Platform)+":"+item.WorkID)
+	t.mu.Unlock()
+}
+
+// notify 唤醒调度协程重新检查追踪列表
+func (t *Tracker) notify() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
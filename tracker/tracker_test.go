@@ -0,0 +1,205 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	videosdk "github.com/caojianfei/parser"
+	"github.com/caojianfei/parser/storage"
+)
+
+// fakeSDK 返回固定统计数据的SDK，视频ID为bad时解析失败
+type fakeSDK struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (s *fakeSDK) RegisterParser(videosdk.Parser) error       { return nil }
+func (s *fakeSDK) GetSupportedPlatforms() []videosdk.Platform { return nil }
+func (s *fakeSDK) SetTimeout(time.Duration)                   {}
+func (s *fakeSDK) SetUserAgent(string)                        {}
+
+func (s *fakeSDK) ParseVideo(ctx context.Context, req *videosdk.ParseRequest) (*videosdk.ParseResponse, error) {
+	s.mu.Lock()
+	s.requests = append(s.requests, req.VideoID)
+	s.mu.Unlock()
+
+	if req.VideoID == "bad" {
+		return &videosdk.ParseResponse{Code: videosdk.ErrCodeRateLimited, Error: "too many requests", Time: time.Now()}, nil
+	}
+	return &videosdk.ParseResponse{
+		Success: true,
+		Data: &videosdk.VideoInfo{
+			ID:       req.VideoID,
+			Platform: req.Platform,
+			Stats:    videosdk.VideoStats{PlayCount: 100, LikeCount: 10, CommentCount: unknown, ShareCount: 1, CollectCount: 0},
+		},
+		Time: time.Now(),
+	}, nil
+}
+
+// received 获取已解析的视频ID
+func (s *fakeSDK) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// newTestTracker 使用临时归档创建不抖动、不限流的追踪器
+func newTestTracker(t *testing.T, sdk videosdk.SDK) (*Tracker, *storage.Store) {
+	t.Helper()
+	store, err := storage.Open(filepath.Join(t.TempDir(), "archive.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	tracker := New(sdk, store, Config{
+		Interval:  time.Hour,
+		Jitter:    -1,
+		RateLimit: videosdk.LimitRule{Rate: 1000, Burst: 100},
+	})
+	return tracker, store
+}
+
+func TestRunOnce(t *testing.T) {
+	ctx := context.Background()
+	sdk := &fakeSDK{}
+	tracker, store := newTestTracker(t, sdk)
+
+	for _, item := range []*storage.WatchItem{
+		{Platform: videosdk.PlatformDouyin, WorkID: "due"},
+		{Platform: videosdk.PlatformDouyin, WorkID: "custom", Interval: 10 * time.Minute},
+		{Platform: videosdk.PlatformKuaishou, WorkID: "bad"},
+		{Platform: videosdk.PlatformDouyin, WorkID: "later", NextRun: time.Now().Add(time.Hour)},
+	} {
+		if err := store.SaveWatchItem(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before := time.Now()
+	polled, err := tracker.RunOnce(ctx)
+	after := time.Now()
+	if polled != 2 {
+		t.Errorf("polled = %d, want 2", polled)
+	}
+	if videosdk.ErrorCodeOf(err) != videosdk.ErrCodeRateLimited {
+		t.Errorf("err = %v, want rate_limited error for the failed work", err)
+	}
+	if got := sdk.received(); len(got) != 3 {
+		t.Errorf("parsed %q, want only the due works", got)
+	}
+
+	tests := []struct {
+		workID   string
+		interval time.Duration // 距离下次采集的间隔，0表示未采集
+		failures int
+	}{
+		{"due", time.Hour, 0},
+		{"custom", 10 * time.Minute, 0},
+		{"bad", 2 * time.Hour, 1}, // 失败后间隔翻倍
+		{"later", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.workID, func(t *testing.T) {
+			platform := videosdk.PlatformDouyin
+			if tt.workID == "bad" {
+				platform = videosdk.PlatformKuaishou
+			}
+			item, err := store.GetWatchItem(ctx, platform, tt.workID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if item.Failures != tt.failures || (tt.failures > 0) != (item.LastError != "") {
+				t.Errorf("failures = %d, last error = %q", item.Failures, item.LastError)
+			}
+
+			_, snapshotErr := store.LatestSnapshot(ctx, platform, tt.workID)
+			if tt.interval == 0 {
+				if !item.LastRun.IsZero() {
+					t.Errorf("last run = %v, want not polled", item.LastRun)
+				}
+				return
+			}
+			if item.LastRun.Before(before) || item.LastRun.After(after) {
+				t.Errorf("last run = %v, want between %v and %v", item.LastRun, before, after)
+			}
+			if item.NextRun.Before(before.Add(tt.interval)) || item.NextRun.After(after.Add(tt.interval)) {
+				t.Errorf("next run in %s, want %s", item.NextRun.Sub(item.LastRun), tt.interval)
+			}
+			if wantSnapshot := tt.failures == 0; wantSnapshot != (snapshotErr == nil) {
+				t.Errorf("snapshot err = %v", snapshotErr)
+			}
+		})
+	}
+
+	// 已采集的作品未到期，再次执行时不会重复采集
+	polled, err = tracker.RunOnce(ctx)
+	if polled != 0 || err != nil || len(sdk.received()) != 3 {
+		t.Errorf("second run: polled = %d, err = %v, parsed %q", polled, err, sdk.received())
+	}
+
+	series, err := tracker.Series(ctx, videosdk.PlatformDouyin, "due", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series.Points) != 1 || series.Points[0].Stats.PlayCount != 100 || series.Points[0].Stats.CommentCount != unknown {
+		t.Errorf("series = %+v", series)
+	}
+}
+
+func TestRunOnceCanceled(t *testing.T) {
+	tracker, store := newTestTracker(t, &fakeSDK{})
+	if err := store.SaveWatchItem(context.Background(), &storage.WatchItem{Platform: videosdk.PlatformDouyin, WorkID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if polled, err := tracker.RunOnce(ctx); polled != 0 || !errors.Is(err, context.Canceled) {
+		t.Errorf("polled = %d, err = %v, want context.Canceled", polled, err)
+	}
+	item, err := store.GetWatchItem(context.Background(), videosdk.PlatformDouyin, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !item.LastRun.IsZero() || item.Failures != 0 {
+		t.Errorf("canceled run recorded: %+v", item)
+	}
+}
+
+func TestWatchValidation(t *testing.T) {
+	tracker, _ := newTestTracker(t, &fakeSDK{})
+	ctx := context.Background()
+
+	item, err := tracker.Watch(ctx, &storage.WatchItem{URL: "https://www.kuaishou.com/short-video/3xk8fz5m2q9wdqa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Platform != videosdk.PlatformKuaishou || item.WorkID != "3xk8fz5m2q9wdqa" || item.NextRun.IsZero() {
+		t.Errorf("watch item = %+v", item)
+	}
+
+	tests := []struct {
+		name string
+		item *storage.WatchItem
+	}{
+		{"nil", nil},
+		{"empty", &storage.WatchItem{}},
+		{"short link", &storage.WatchItem{URL: "https://v.douyin.com/iRNBho6u/"}},
+		{"interval too short", &storage.WatchItem{Platform: videosdk.PlatformDouyin, WorkID: "1", Interval: time.Second}},
+		{"negative interval", &storage.WatchItem{Platform: videosdk.PlatformDouyin, WorkID: "1", Interval: -time.Hour}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tracker.Watch(ctx, tt.item); videosdk.ErrorCodeOf(err) != videosdk.ErrCodeInvalidRequest {
+				t.Errorf("err = %v, want invalid_request", err)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package videosdk
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"time"
 )
 
@@ -48,20 +50,24 @@ type ParseRequest struct {
 	URL      string   `json:"url"`      // 视频URL（可选，用于从URL提取ID）
 	Cookie   string   `json:"cookie"`   // Cookie（某些平台需要）
 	Proxy    string   `json:"proxy"`    // 代理地址（可选）
-	Source   bool     `json:"source"`   // 是否获取原始数据
+	Source   bool     `json:"source"`   // 是否获取原始数据，设置后VideoInfo.Raw返回原始JSON
+
+	XsecToken  string `json:"xsec_token,omitempty"`  // 小红书笔记的访问令牌，优先于URL中的xsec_token
+	XsecSource string `json:"xsec_source,omitempty"` // 小红书访问令牌的来源，如pc_feed、pc_share
 }
 
 // VideoInfo 统一的视频信息结构
 type VideoInfo struct {
 	// 基础信息
-	ID          string    `json:"id"`          // 视频ID
-	Title       string    `json:"title"`       // 视频标题
-	Description string    `json:"description"` // 视频描述
-	Type        VideoType `json:"type"`        // 视频类型
-	Platform    Platform  `json:"platform"`    // 平台
-	URL         string    `json:"url"`         // 视频页面URL
-	CreateTime  time.Time `json:"create_time"` // 创建时间
-	Duration    string    `json:"duration"`    // 视频时长
+	ID          string        `json:"id"`          // 视频ID
+	Title       string        `json:"title"`       // 视频标题
+	Description string        `json:"description"` // 视频描述
+	Type        VideoType     `json:"type"`        // 视频类型
+	Platform    Platform      `json:"platform"`    // 平台
+	URL         string        `json:"url"`         // 视频页面URL
+	CreateTime  time.Time     `json:"create_time"` // 创建时间（Asia/Shanghai时区，未知时为零值）
+	UpdateTime  time.Time     `json:"update_time"` // 最后更新时间（Asia/Shanghai时区，未知时为零值）
+	Duration    time.Duration `json:"duration"`    // 视频时长（纳秒，未知时为0）
 
 	// 媒体信息
 	Downloads []DownloadItem `json:"downloads"` // 媒体下载链接列表
@@ -83,6 +89,27 @@ type VideoInfo struct {
 
 	// 扩展信息
 	Extra map[string]interface{} `json:"extra"` // 平台特有的扩展信息
+
+	// 相关条目
+	Related []RelatedItem `json:"related,omitempty"` // 作者主页等相关条目，带有访问所需的最新令牌
+
+	// 原始数据
+	Raw json.RawMessage `json:"raw,omitempty"` // 请求设置Source时返回后端响应的data部分，是后端提取后的作品数据而不是平台原始数据
+}
+
+// FormattedDuration 获取"HH:MM:SS"格式的视频时长
+func (v *VideoInfo) FormattedDuration() string {
+	return FormatDuration(v.Duration)
+}
+
+// RelatedItem 与作品相关的条目
+type RelatedItem struct {
+	Type   string `json:"type"`             // 条目类型：note（作品）、author（作者主页）
+	ID     string `json:"id"`               // 条目ID
+	Title  string `json:"title,omitempty"`  // 标题或昵称
+	URL    string `json:"url"`              // 带令牌的访问链接
+	Token  string `json:"token,omitempty"`  // 访问令牌（小红书的xsec_token）
+	Source string `json:"source,omitempty"` // 令牌来源（小红书的xsec_source）
 }
 
 // AuthorInfo 作者信息
@@ -94,15 +121,17 @@ type AuthorInfo struct {
 	Avatar    string `json:"avatar"`    // 头像URL
 	Signature string `json:"signature"` // 个人签名
 	Age       int    `json:"age"`       // 年龄
+
+	FollowerCount Count `json:"follower_count"` // 粉丝数（未知时为CountUnknown）
 }
 
-// VideoStats 视频统计信息
+// VideoStats 视频统计信息（未知或被隐藏的数量为CountUnknown）
 type VideoStats struct {
-	PlayCount    int64 `json:"play_count"`    // 播放量
-	LikeCount    int64 `json:"like_count"`    // 点赞数
-	CommentCount int64 `json:"comment_count"` // 评论数
-	ShareCount   int64 `json:"share_count"`   // 分享数
-	CollectCount int64 `json:"collect_count"` // 收藏数
+	PlayCount    Count `json:"play_count"`    // 播放量
+	LikeCount    Count `json:"like_count"`    // 点赞数
+	CommentCount Count `json:"comment_count"` // 评论数
+	ShareCount   Count `json:"share_count"`   // 分享数
+	CollectCount Count `json:"collect_count"` // 收藏数
 }
 
 // MusicInfo 音乐信息
@@ -118,8 +147,11 @@ type ParseResponse struct {
 	Success bool       `json:"success"`         // 是否成功
 	Message string     `json:"message"`         // 响应消息
 	Data    *VideoInfo `json:"data,omitempty"`  // 视频信息
+	Code    ErrorCode  `json:"code,omitempty"`  // 错误类别
 	Error   string     `json:"error,omitempty"` // 错误信息
 	Time    time.Time  `json:"time"`            // 响应时间
+
+	Warnings []string `json:"warnings,omitempty"` // 宽松模式下发现的后端响应结构问题
 }
 
 // Parser 平台解析器接口
@@ -137,6 +169,24 @@ type Parser interface {
 	ValidateRequest(req *ParseRequest) error
 }
 
+// BackendProvider 基于后端服务的解析器可选实现的接口，用于按后端服务限流和监控
+type BackendProvider interface {
+	// BaseURL 获取后端服务地址
+	BaseURL() string
+}
+
+// TransportSetter 解析器可选实现的接口，用于替换访问后端服务的HTTP传输层（如录制回放、测试桩）
+type TransportSetter interface {
+	// SetTransport 设置HTTP传输层
+	SetTransport(transport http.RoundTripper)
+}
+
+// HealthChecker 解析器可选实现的健康检查接口，用于多后端故障转移
+type HealthChecker interface {
+	// HealthCheck 检查后端服务是否可用
+	HealthCheck(ctx context.Context) error
+}
+
 // SDK 主SDK接口
 type SDK interface {
 	// RegisterParser 注册平台解析器
@@ -154,3 +204,13 @@ type SDK interface {
 	// SetUserAgent 设置User-Agent
 	SetUserAgent(userAgent string)
 }
+
+// BatchSDK 支持批量解析的SDK，*VideoSDK实现了该接口
+//
+// 批量解析单独定义为扩展接口，SDK接口保持不变，已有的SDK实现和mock无需修改。
+type BatchSDK interface {
+	SDK
+
+	// ParseBatch 并发解析多个视频，返回结果与请求一一对应
+	ParseBatch(ctx context.Context, reqs []*ParseRequest) []*ParseResponse
+}